@@ -2,11 +2,13 @@ package acceptance
 
 import (
 	"context"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"net/http/httptest"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -27,9 +29,14 @@ func TestSlackOrbBinary(t *testing.T) {
 	fix := setupE2E(ctx, t)
 
 	today := time.Now().Format("01/02/2006")
+	testResults, err := filepath.Abs("../junit/testdata")
+	assert.NilError(t, err)
+	windowStart := time.Now().UTC().Add(6 * time.Hour)
+	windowOutsideNow := windowStart.Format("15:04") + "-" + windowStart.Add(time.Hour).Format("15:04")
 
 	tests := []struct {
 		name                      string
+		args                      []string
 		environment               map[string]string
 		expectedExitCode          int
 		expectedOutput            string
@@ -68,20 +75,44 @@ func TestSlackOrbBinary(t *testing.T) {
 		expectedExitCode:          0,
 		expectedOutput:            "Successfully posted message to channel: test-channel",
 		expectedSlackAPICallCount: 1,
+	}, {
+		name: "Test failure summary template",
+		environment: map[string]string{
+			"SLACK_ACCESS_TOKEN":     "test-token",
+			"SLACK_STR_CHANNEL":      "test-channel",
+			"CCI_STATUS":             "fail",
+			"SLACK_STR_EVENT":        "fail",
+			"SLACK_STR_TEMPLATE":     "test_failure_summary_1",
+			"SLACK_STR_TEST_RESULTS": testResults,
+			"SLACK_BOOL_DEBUG":       "true",
+		},
+		expectedExitCode:          0,
+		expectedOutput:            "api.UsersTest.test_delete",
+		expectedSlackAPICallCount: 1,
 	}, {
 		name: "Missing slack token",
 		environment: map[string]string{
 			"SLACK_STR_CHANNEL": "test-channel",
 		},
-		expectedExitCode: 1,
+		expectedExitCode: 2,
 		expectedOutput:   "In order to use the Slack Orb an OAuth token must be present via the SLACK_ACCESS_TOKEN environment variable.",
 	}, {
 		name: "Missing slack channel",
 		environment: map[string]string{
 			"SLACK_ACCESS_TOKEN": "test-token",
 		},
-		expectedExitCode: 1,
+		expectedExitCode: 2,
 		expectedOutput:   `No channel was provided. Please provide one or more channels using the "SLACK_STR_CHANNEL" environment variable or the "channel" parameter.`,
+	}, {
+		name: "Unknown CI provider",
+		environment: map[string]string{
+			"SLACK_ACCESS_TOKEN":    "test-token",
+			"SLACK_STR_CHANNEL":     "test-channel",
+			"CCI_STATUS":            "pass",
+			"SLACK_STR_CI_PROVIDER": "gitlab-ci",
+		},
+		expectedExitCode: 2,
+		expectedOutput:   `unknown CI provider "gitlab-ci" in SLACK_STR_CI_PROVIDER`,
 	}, {
 		name:             "Job status does not match",
 		expectedExitCode: 0,
@@ -130,6 +161,144 @@ func TestSlackOrbBinary(t *testing.T) {
 			"SLACK_ORB_TIME_FORMAT":     "01/02/2006",
 			"SLACK_STR_TEMPLATE_INLINE": "{\"blocks\":[{\"type\":\"section\",\"text\":{\"type\":\"mrkdwn\",\"text\":\"Today's date is $SLACK_ORB_TIME_NOW\"}}]}",
 		},
+	}, {
+		name:                      "JSON result output",
+		expectedExitCode:          0,
+		expectedOutput:            `"permalink": "https://fake-slack.example.com/archives/test-channel/p1700000000`,
+		expectedSlackAPICallCount: 2,
+		environment: map[string]string{
+			"SLACK_ACCESS_TOKEN": "test-token",
+			"SLACK_STR_CHANNEL":  "test-channel",
+			"CCI_STATUS":         "pass",
+			"SLACK_STR_EVENT":    "pass",
+			"SLACK_STR_OUTPUT":   "json",
+		},
+	}, {
+		name:             "JSON result output when skipped",
+		expectedExitCode: 0,
+		expectedOutput:   `"reason": "status_mismatch"`,
+		environment: map[string]string{
+			"SLACK_ACCESS_TOKEN": "test-token",
+			"SLACK_STR_CHANNEL":  "test-channel",
+			"CCI_STATUS":         "fail",
+			"SLACK_STR_EVENT":    "pass",
+			"SLACK_STR_OUTPUT":   "json",
+		},
+	}, {
+		name:             "Outside of the delivery window",
+		expectedExitCode: 0,
+		expectedOutput:   fmt.Sprintf(`Exiting without posting to Slack: The current time is outside of the delivery window %q.`, windowOutsideNow),
+		environment: map[string]string{
+			"SLACK_ACCESS_TOKEN":        "test-token",
+			"SLACK_STR_CHANNEL":         "test-channel",
+			"CCI_STATUS":                "pass",
+			"SLACK_STR_EVENT":           "pass",
+			"SLACK_STR_TIMEZONE":        "UTC",
+			"SLACK_STR_DELIVERY_WINDOW": windowOutsideNow,
+		},
+	}, {
+		name:                      "Failure outside of the delivery window",
+		expectedExitCode:          0,
+		expectedOutput:            "Successfully posted message to channel: test-channel",
+		expectedSlackAPICallCount: 1,
+		environment: map[string]string{
+			"SLACK_ACCESS_TOKEN":               "test-token",
+			"SLACK_STR_CHANNEL":                "test-channel",
+			"CCI_STATUS":                       "fail",
+			"SLACK_STR_EVENT":                  "fail",
+			"SLACK_STR_TIMEZONE":               "UTC",
+			"SLACK_STR_DELIVERY_WINDOW":        windowOutsideNow,
+			"SLACK_STR_DELIVERY_WINDOW_POLICY": "failures-only",
+		},
+	}, {
+		name:             "Skipped by the commit message",
+		expectedExitCode: 0,
+		expectedOutput:   "Exiting without posting to Slack: The commit message contains [skip slack].",
+		environment: map[string]string{
+			"SLACK_ACCESS_TOKEN":       "test-token",
+			"SLACK_STR_CHANNEL":        "test-channel",
+			"CCI_STATUS":               "pass",
+			"SLACK_STR_EVENT":          "pass",
+			"SLACK_STR_COMMIT_MESSAGE": "Update the README [skip slack]",
+		},
+	}, {
+		name:                      "Channel requested by the commit message",
+		expectedExitCode:          0,
+		expectedOutput:            "Successfully posted message to channel: api-team",
+		expectedSlackAPICallCount: 2,
+		environment: map[string]string{
+			"SLACK_ACCESS_TOKEN":       "test-token",
+			"SLACK_STR_CHANNEL":        "test-channel",
+			"CCI_STATUS":               "pass",
+			"SLACK_STR_EVENT":          "pass",
+			"SLACK_STR_COMMIT_MESSAGE": "Release the API [slack:#api-team]",
+		},
+	}, {
+		name:             "Custom exit code when skipped",
+		expectedExitCode: 78,
+		expectedOutput:   `Exiting without posting to Slack: The job status "fail" does not match the status set to send alerts "pass".`,
+		environment: map[string]string{
+			"SLACK_ACCESS_TOKEN":       "test-token",
+			"SLACK_STR_CHANNEL":        "test-channel",
+			"CCI_STATUS":               "fail",
+			"SLACK_STR_EVENT":          "pass",
+			"SLACK_INT_SKIP_EXIT_CODE": "78",
+		},
+	}, {
+		name:             "Skip exit code used for errors is a configuration error",
+		expectedExitCode: 2,
+		expectedOutput:   "invalid value for --skip-exit-code: 4. Codes 1 to 4 are used for errors",
+		environment: map[string]string{
+			"SLACK_ACCESS_TOKEN":       "test-token",
+			"SLACK_STR_CHANNEL":        "test-channel",
+			"CCI_STATUS":               "fail",
+			"SLACK_STR_EVENT":          "pass",
+			"SLACK_INT_SKIP_EXIT_CODE": "4",
+		},
+	}, {
+		name:             "Invalid status is a configuration error",
+		expectedExitCode: 2,
+		expectedOutput:   "invalid value for CCI_STATUS: unknown",
+		environment: map[string]string{
+			"SLACK_ACCESS_TOKEN": "test-token",
+			"SLACK_STR_CHANNEL":  "test-channel",
+			"CCI_STATUS":         "unknown",
+		},
+	}, {
+		name:             "Missing template is a render error",
+		expectedExitCode: 3,
+		expectedOutput:   "the template does not exist: not_a_template",
+		environment: map[string]string{
+			"SLACK_ACCESS_TOKEN": "test-token",
+			"SLACK_STR_CHANNEL":  "test-channel",
+			"CCI_STATUS":         "pass",
+			"SLACK_STR_EVENT":    "pass",
+			"SLACK_STR_TEMPLATE": "not_a_template",
+		},
+	}, {
+		name:                      "Slack error is a delivery error",
+		expectedExitCode:          4,
+		expectedOutput:            "channel_not_found",
+		expectedSlackAPICallCount: 1,
+		environment: map[string]string{
+			"SLACK_ACCESS_TOKEN":       "test-token",
+			"SLACK_STR_CHANNEL":        fakeslack.UnknownChannel,
+			"CCI_STATUS":               "pass",
+			"SLACK_STR_EVENT":          "pass",
+			"SLACK_BOOL_IGNORE_ERRORS": "false",
+		},
+	}, {
+		name:                      "Slack error is ignored",
+		expectedExitCode:          0,
+		expectedOutput:            "channel_not_found",
+		expectedSlackAPICallCount: 1,
+		environment: map[string]string{
+			"SLACK_ACCESS_TOKEN":       "test-token",
+			"SLACK_STR_CHANNEL":        fakeslack.UnknownChannel,
+			"CCI_STATUS":               "pass",
+			"SLACK_STR_EVENT":          "pass",
+			"SLACK_BOOL_IGNORE_ERRORS": "true",
+		},
 	}}
 
 	for _, tt := range tests {
@@ -140,37 +309,586 @@ func TestSlackOrbBinary(t *testing.T) {
 				slackAPIServer.Close()
 			})
 
-			cmd := exec.Command(fix.slackOrbPath, "notify")
-
-			comparableOutput := &strings.Builder{}
-			w := io.MultiWriter(os.Stdout, comparableOutput)
-			cmd.Stdout = w
-			cmd.Stderr = w
-			cmd.Env = append(cmd.Environ(), "TEST_SLACK_API_BASE_URL="+slackAPIServer.URL)
-			for key, value := range tt.environment {
-				cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
+			args := tt.args
+			if args == nil {
+				args = []string{"notify"}
 			}
+			exitCode, output := fix.run(t, slackAPIServer.URL, args, tt.environment)
 
-			assert.Assert(t, cmd.Start())
-			t.Cleanup(func() {
-				_ = cmd.Process.Kill()
-			})
-
-			err := cmd.Wait()
-			if tt.expectedExitCode == 0 {
-				assert.NilError(t, err)
-			}
-
-			assert.Check(t, cmp.Equal(cmd.ProcessState.ExitCode(), tt.expectedExitCode))
+			assert.Check(t, cmp.Equal(exitCode, tt.expectedExitCode))
 			assert.Check(t, cmp.Equal(len(fix.slackAPI.AllRequests()), tt.expectedSlackAPICallCount))
 
 			if tt.expectedOutput != "" {
-				assert.Check(t, cmp.Contains(comparableOutput.String(), tt.expectedOutput))
+				assert.Check(t, cmp.Contains(output, tt.expectedOutput))
 			}
 		})
 	}
 }
 
+func TestScheduleAndCancel(t *testing.T) {
+	skip.If(t, testing.Short, "Test compiles and executes local binaries")
+
+	ctx := testcontext.Background()
+	fix := setupE2E(ctx, t)
+
+	slackAPIServer := httptest.NewServer(fix.slackAPI.Handler())
+	t.Cleanup(slackAPIServer.Close)
+
+	resultFile := filepath.Join(t.TempDir(), "result.json")
+	environment := map[string]string{
+		"SLACK_ACCESS_TOKEN":    "test-token",
+		"SLACK_STR_CHANNEL":     "test-channel",
+		"CCI_STATUS":            "fail",
+		"SLACK_STR_EVENT":       "fail",
+		"SLACK_STR_RESULT_FILE": resultFile,
+	}
+
+	exitCode, output := fix.run(t, slackAPIServer.URL, []string{"notify", "--post-at", "09:00", "--timezone", "Europe/Berlin"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, "Successfully scheduled message to channel: test-channel"))
+	assert.Check(t, cmp.Len(fix.slackAPI.Scheduled(), 1))
+
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{"cancel-scheduled"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, "Successfully cancelled scheduled message"))
+	assert.Check(t, cmp.Len(fix.slackAPI.Scheduled(), 0))
+
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{"cancel-scheduled"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, "was already posted or cancelled"))
+}
+
+func TestAttachments(t *testing.T) {
+	skip.If(t, testing.Short, "Test compiles and executes local binaries")
+
+	ctx := testcontext.Background()
+	fix := setupE2E(ctx, t)
+
+	slackAPIServer := httptest.NewServer(fix.slackAPI.Handler())
+	t.Cleanup(slackAPIServer.Close)
+
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "junit.xml"), []byte("<testsuites/>"), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "build.log"), []byte(strings.Repeat("x", 2048)), 0o600))
+
+	exitCode, output := fix.run(t, slackAPIServer.URL, []string{
+		"notify", "--attach", filepath.Join(dir, "*.xml"), "--attach", filepath.Join(dir, "build.log"), "--attach-max-size", "1KB",
+	}, map[string]string{
+		"SLACK_ACCESS_TOKEN": "test-token",
+		"SLACK_STR_CHANNEL":  "test-channel",
+		"CCI_STATUS":         "fail",
+		"SLACK_STR_EVENT":    "fail",
+	})
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, "Not attaching "+filepath.Join(dir, "build.log")))
+
+	uploads := fix.slackAPI.Uploads()
+	assert.Assert(t, cmp.Len(uploads, 1))
+	assert.Check(t, cmp.DeepEqual(uploads[0], fakeslack.Upload{
+		FileID:    "F00000001",
+		Filename:  "junit.xml",
+		Content:   []byte("<testsuites/>"),
+		ChannelID: "test-channel",
+		ThreadTS:  "1700000000.000001",
+		Completed: true,
+	}))
+}
+
+func TestPathPatternDryRun(t *testing.T) {
+	skip.If(t, testing.Short, "Test compiles and executes local binaries")
+
+	ctx := testcontext.Background()
+	fix := setupE2E(ctx, t)
+
+	slackAPIServer := httptest.NewServer(fix.slackAPI.Handler())
+	t.Cleanup(slackAPIServer.Close)
+
+	// Build a repository where the last commit changed a single service
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"commit", "--quiet", "--allow-empty", "-m", "Initial commit"},
+		{"add", "."},
+		{"commit", "--quiet", "-m", "Change the API"},
+	} {
+		if args[0] == "add" {
+			assert.NilError(t, os.MkdirAll(filepath.Join(repo, "services", "api"), 0o700))
+			assert.NilError(t, os.WriteFile(filepath.Join(repo, "services", "api", "main.go"), []byte("package main"), 0o600))
+		}
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Jane Doe", "-c", "user.email=jane@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		assert.NilError(t, err, string(out))
+	}
+
+	environment := map[string]string{
+		"SLACK_ACCESS_TOKEN": "test-token",
+		"SLACK_STR_CHANNEL":  "test-channel",
+		"CCI_STATUS":         "pass",
+		"SLACK_STR_EVENT":    "pass",
+		"SLACK_STR_OUTPUT":   "json",
+		"GIT_DIR":            filepath.Join(repo, ".git"),
+		"GIT_WORK_TREE":      repo,
+	}
+
+	environment["SLACK_STR_PATH_PATTERN"] = "services/api/**,docs/**"
+	exitCode, output := fix.run(t, slackAPIServer.URL, []string{"notify", "--dry-run"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, "Dry run: the message would be posted to channel: test-channel"))
+	assert.Check(t, cmp.Contains(output, `"decision": "dry_run"`))
+	assert.Check(t, cmp.Contains(output, `"services/api/main.go"`))
+	assert.Check(t, cmp.Len(fix.slackAPI.AllRequests(), 0))
+
+	environment["SLACK_STR_PATH_PATTERN"] = "services/web/**"
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{"notify", "--dry-run"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, `"reason": "no_matching_paths"`))
+
+	t.Run("base revision not fetched", func(t *testing.T) {
+		environment["SLACK_STR_PATH_BASE"] = "HEAD~5"
+		exitCode, output := fix.run(t, slackAPIServer.URL, []string{"notify", "--dry-run"}, environment)
+		assert.Check(t, cmp.Equal(exitCode, 0))
+		assert.Check(t, cmp.Contains(output, `Unable to determine the changed files: the --path-base revision "HEAD~5"`))
+		assert.Check(t, cmp.Contains(output, "Dry run: the message would be posted to channel: test-channel"))
+	})
+}
+
+func TestRecordStatus(t *testing.T) {
+	skip.If(t, testing.Short, "Test compiles and executes local binaries")
+
+	ctx := testcontext.Background()
+	fix := setupE2E(ctx, t)
+
+	slackAPIServer := httptest.NewServer(fix.slackAPI.Handler())
+	t.Cleanup(slackAPIServer.Close)
+
+	environment := map[string]string{
+		"SLACK_ACCESS_TOKEN":    "test-token",
+		"SLACK_STR_CHANNEL":     "test-channel",
+		"SLACK_STR_EVENT":       "fail",
+		"SLACK_STR_STATUS_FILE": filepath.Join(t.TempDir(), "SLACK_JOB_STATUS"),
+	}
+
+	exitCode, output := fix.run(t, slackAPIServer.URL, []string{"status"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 2))
+	assert.Check(t, cmp.Contains(output, "no job status was recorded"))
+
+	// A step running with "when: on_fail" followed by one running with "when: always"
+	exitCode, _ = fix.run(t, slackAPIServer.URL, []string{"record-status", "fail"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{"record-status", "success"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, `Keeping the job status "fail"`))
+
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{"status"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Equal(output, "fail\n"))
+
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{"notify"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, "Successfully posted message to channel: test-channel"))
+
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{"record-status", "unknown"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 2))
+	assert.Check(t, cmp.Contains(output, `invalid job status "unknown"`))
+}
+
+func TestDedupe(t *testing.T) {
+	skip.If(t, testing.Short, "Test compiles and executes local binaries")
+
+	ctx := testcontext.Background()
+	fix := setupE2E(ctx, t)
+
+	slackAPIServer := httptest.NewServer(fix.slackAPI.Handler())
+	t.Cleanup(slackAPIServer.Close)
+
+	environment := map[string]string{
+		"SLACK_ACCESS_TOKEN":      "test-token",
+		"SLACK_STR_CHANNEL":       "test-channel",
+		"CCI_STATUS":              "fail",
+		"SLACK_STR_EVENT":         "fail",
+		"CIRCLE_PROJECT_REPONAME": "repo",
+		"CIRCLE_BRANCH":           "main",
+		"CIRCLE_JOB":              "test",
+		"SLACK_STR_DEDUPE_WINDOW": "1h",
+		"SLACK_STR_DEDUPE_STATE":  filepath.Join(t.TempDir(), "dedupe.json"),
+		"SLACK_STR_OUTPUT":        "json",
+	}
+
+	exitCode, output := fix.run(t, slackAPIServer.URL, []string{"notify"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, `"occurrences": 1`))
+
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{"notify"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, `The notification "repo/main/test/fail" was already sent in the last 1h0m0s`))
+	assert.Check(t, cmp.Contains(output, `"reason": "duplicate"`))
+	// The message and its permalink
+	assert.Check(t, cmp.Len(fix.slackAPI.AllRequests(), 2))
+
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{"notify", "--dedupe-policy", "thread"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, "Successfully replied to the first notification in channel: test-channel"))
+	assert.Check(t, cmp.Contains(output, `"thread_ts": "1700000000.000001"`))
+	assert.Check(t, cmp.Contains(output, `"occurrences": 3`))
+
+	update := fix.slackAPI.LastRequest()
+	assert.Check(t, cmp.Equal(update.URL.Path, "/chat.update"))
+	assert.Check(t, cmp.Contains(string(update.Body), ":repeat: Failed 3 times since"))
+}
+
+func TestWorkflowSummary(t *testing.T) {
+	skip.If(t, testing.Short, "Test compiles and executes local binaries")
+
+	ctx := testcontext.Background()
+	fix := setupE2E(ctx, t)
+
+	slackAPIServer := httptest.NewServer(fix.slackAPI.Handler())
+	t.Cleanup(slackAPIServer.Close)
+
+	recordDir := t.TempDir()
+	for _, job := range []map[string]string{
+		{"CIRCLE_JOB": "build", "CCI_STATUS": "pass", "CIRCLE_BUILD_URL": "https://circleci.com/gh/org/repo/1"},
+		{"CIRCLE_JOB": "test", "CIRCLE_NODE_INDEX": "0", "CCI_STATUS": "fail", "CIRCLE_BUILD_URL": "https://circleci.com/gh/org/repo/2"},
+		// A passing node does not replace the record of a failed one
+		{"CIRCLE_JOB": "test", "CIRCLE_NODE_INDEX": "1", "CCI_STATUS": "pass", "CIRCLE_BUILD_URL": "https://circleci.com/gh/org/repo/2"},
+	} {
+		job["SLACK_STR_RECORD_DIR"] = recordDir
+		exitCode, output := fix.run(t, slackAPIServer.URL, []string{"notify", "--record-only"}, job)
+		assert.Check(t, cmp.Equal(exitCode, 0))
+		assert.Check(t, cmp.Contains(output, "Recorded the status"))
+	}
+	assert.Check(t, cmp.Len(fix.slackAPI.AllRequests(), 0))
+
+	exitCode, output := fix.run(t, slackAPIServer.URL, []string{"summarize", "--record-dir", recordDir, "--output", "json"}, map[string]string{
+		"SLACK_ACCESS_TOKEN": "test-token",
+		"SLACK_STR_CHANNEL":  "test-channel",
+		"CIRCLE_JOB":         "notify",
+		"CCI_STATUS":         "pass",
+	})
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, "Summarizing 2 jobs"))
+	assert.Check(t, cmp.Contains(output, `"decision": "posted"`))
+
+	// The message and its permalink
+	requests := fix.slackAPI.AllRequests()
+	assert.Assert(t, cmp.Len(requests, 2))
+	body := string(requests[0].Body)
+	assert.Check(t, cmp.Contains(body, "Workflow Summary"))
+	assert.Check(t, cmp.Contains(body, "The circleci workflow of"))
+	assert.Check(t, cmp.Contains(body, `:white_check_mark: \u003chttps://circleci.com/gh/org/repo/1|build\u003e`))
+	assert.Check(t, cmp.Contains(body, `:x: \u003chttps://circleci.com/gh/org/repo/2|test\u003e`))
+	assert.Check(t, cmp.Contains(body, "*Failed*: 1 of 2 jobs"))
+
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{"summarize", "--record-dir", t.TempDir()}, map[string]string{
+		"SLACK_ACCESS_TOKEN": "test-token",
+		"SLACK_STR_CHANNEL":  "test-channel",
+	})
+	assert.Check(t, cmp.Equal(exitCode, 2))
+	assert.Check(t, cmp.Contains(output, "no job records were found"))
+}
+
+func TestReact(t *testing.T) {
+	skip.If(t, testing.Short, "Test compiles and executes local binaries")
+
+	ctx := testcontext.Background()
+	fix := setupE2E(ctx, t)
+
+	slackAPIServer := httptest.NewServer(fix.slackAPI.Handler())
+	t.Cleanup(slackAPIServer.Close)
+
+	environment := map[string]string{
+		"SLACK_ACCESS_TOKEN":    "test-token",
+		"SLACK_STR_CHANNEL":     "test-channel",
+		"CCI_STATUS":            "pass",
+		"SLACK_STR_EVENT":       "always",
+		"SLACK_STR_RESULT_FILE": filepath.Join(t.TempDir(), "result.json"),
+	}
+
+	exitCode, _ := fix.run(t, slackAPIServer.URL, []string{"notify"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+
+	exitCode, output := fix.run(t, slackAPIServer.URL, []string{"react", "--add", ":hourglass:"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, "Added reaction :hourglass: to message 1700000000.000001 in channel: test-channel"))
+	assert.Check(t, cmp.DeepEqual(fix.slackAPI.Reactions("test-channel", "1700000000.000001"), []string{"hourglass"}))
+
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{
+		"react", "--remove", "hourglass", "--add", "white_check_mark", "--add", "hourglass",
+	}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, "Removed reaction hourglass"))
+	assert.Check(t, cmp.DeepEqual(fix.slackAPI.Reactions("test-channel", "1700000000.000001"),
+		[]string{"white_check_mark", "hourglass"}))
+
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{"react", "--add", "white_check_mark"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, "already has the white_check_mark reaction"))
+
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{
+		"react", "--channel", "test-channel", "--ts", "1700000000.000001", "--remove", "x",
+	}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, "has no x reaction"))
+}
+
+func TestDelete(t *testing.T) {
+	skip.If(t, testing.Short, "Test compiles and executes local binaries")
+
+	ctx := testcontext.Background()
+	fix := setupE2E(ctx, t)
+
+	slackAPIServer := httptest.NewServer(fix.slackAPI.Handler())
+	t.Cleanup(slackAPIServer.Close)
+
+	environment := map[string]string{
+		"SLACK_ACCESS_TOKEN":    "test-token",
+		"SLACK_STR_CHANNEL":     "test-channel",
+		"CCI_STATUS":            "pass",
+		"SLACK_STR_EVENT":       "always",
+		"SLACK_STR_RESULT_FILE": filepath.Join(t.TempDir(), "result.json"),
+	}
+
+	exitCode, _ := fix.run(t, slackAPIServer.URL, []string{"notify"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+
+	// The fake messages are posted in 2023
+	exitCode, output := fix.run(t, slackAPIServer.URL, []string{
+		"delete", "--older-than", "876000h", "--output", "json",
+	}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, `"decision": "skipped"`))
+	assert.Check(t, cmp.Contains(output, `"reason": "too_recent"`))
+	assert.Check(t, !fix.slackAPI.Deleted("test-channel", "1700000000.000001"))
+
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{
+		"delete", "--older-than", "10m", "--output", "json",
+	}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, "Successfully deleted message 1700000000.000001 in channel: test-channel"))
+	assert.Check(t, cmp.Contains(output, `"decision": "deleted"`))
+	assert.Check(t, fix.slackAPI.Deleted("test-channel", "1700000000.000001"))
+
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{"delete", "--output", "json"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, `"reason": "already_deleted"`))
+
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{
+		"delete", "--channel", fakeslack.UnknownChannel, "--ts", "1700000000.000001", "--output", "json",
+	}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 4))
+	assert.Check(t, cmp.Contains(output, `"error": "channel_not_found"`))
+}
+
+func TestEphemeral(t *testing.T) {
+	skip.If(t, testing.Short, "Test compiles and executes local binaries")
+
+	ctx := testcontext.Background()
+	fix := setupE2E(ctx, t)
+
+	slackAPIServer := httptest.NewServer(fix.slackAPI.Handler())
+	t.Cleanup(slackAPIServer.Close)
+
+	environment := map[string]string{
+		"SLACK_ACCESS_TOKEN":   "test-token",
+		"SLACK_STR_CHANNEL":    "test-channel",
+		"CCI_STATUS":           "pass",
+		"SLACK_STR_EVENT":      "always",
+		"SLACK_BOOL_EPHEMERAL": "true",
+		"SLACK_STR_USER_MAP":   "jdoe=U0123456789,outsider=" + fakeslack.OutsideUser,
+		"SLACK_STR_OUTPUT":     "json",
+	}
+
+	environment["CIRCLE_USERNAME"] = "jdoe"
+	exitCode, output := fix.run(t, slackAPIServer.URL, []string{"notify"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, "Successfully posted ephemeral message for U0123456789 to channel: test-channel"))
+	assert.Check(t, cmp.Contains(output, `"ephemeral": true`))
+	assert.Check(t, cmp.Equal(fix.slackAPI.LastRequest().URL.Path, "/chat.postEphemeral"))
+
+	environment["CIRCLE_USERNAME"] = "outsider"
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{"notify"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, "as a direct message"))
+	assert.Check(t, cmp.Contains(output, `"fallback": "direct_message"`))
+	assert.Check(t, cmp.Contains(output, `"channel_id": "D`+fakeslack.OutsideUser+`"`))
+
+	t.Run("unmapped user", func(t *testing.T) {
+		environment["CIRCLE_USERNAME"] = "stranger"
+		environment["SLACK_ORB_COMMIT_AUTHOR_EMAIL"] = "stranger@example.com"
+		exitCode, output := fix.run(t, slackAPIServer.URL, []string{"notify"}, environment)
+		assert.Check(t, cmp.Equal(exitCode, 0))
+		assert.Check(t, cmp.Contains(output, `No Slack user is mapped to "stranger" in --user-map`))
+		assert.Check(t, cmp.Contains(output, "as a direct message"))
+		assert.Check(t, cmp.Contains(output, `"channel": "user:stranger@example.com"`))
+		assert.Check(t, cmp.Contains(output, `"channel_id": "DU0STRANGER"`))
+		assert.Check(t, cmp.Contains(output, `"fallback": "direct_message"`))
+
+		delete(environment, "SLACK_ORB_COMMIT_AUTHOR_EMAIL")
+		requests := len(fix.slackAPI.AllRequests())
+		exitCode, output = fix.run(t, slackAPIServer.URL, []string{"notify"}, environment)
+		assert.Check(t, cmp.Equal(exitCode, 0))
+		assert.Check(t, cmp.Contains(output, "Exiting without posting to Slack"))
+		assert.Check(t, cmp.Contains(output, `"decision": "skipped"`))
+		assert.Check(t, cmp.Contains(output, `"reason": "unknown_user"`))
+		assert.Check(t, cmp.Len(fix.slackAPI.AllRequests(), requests))
+	})
+}
+
+func TestRecipients(t *testing.T) {
+	skip.If(t, testing.Short, "Test compiles and executes local binaries")
+
+	ctx := testcontext.Background()
+	fix := setupE2E(ctx, t)
+
+	slackAPIServer := httptest.NewServer(fix.slackAPI.Handler())
+	t.Cleanup(slackAPIServer.Close)
+
+	environment := map[string]string{
+		"SLACK_ACCESS_TOKEN": "test-token",
+		"SLACK_STR_CHANNEL":  "test-channel,user:alice@example.com,user:U0BOB,group:" + fakeslack.UserGroup,
+		"CCI_STATUS":         "pass",
+		"SLACK_STR_EVENT":    "always",
+		"SLACK_STR_OUTPUT":   "json",
+	}
+
+	exitCode, output := fix.run(t, slackAPIServer.URL, []string{"notify"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, "Successfully posted message to channel: test-channel"))
+	assert.Check(t, cmp.Contains(output, "Successfully sent direct message to U0ALICE for recipient: user:alice@example.com"))
+	assert.Check(t, cmp.Contains(output, `"channel_id": "DU0BOB"`))
+	for _, member := range fakeslack.UserGroupMembers {
+		assert.Check(t, cmp.Contains(output, `"user": "`+member+`"`))
+		assert.Check(t, cmp.Contains(output, `"channel_id": "D`+member+`"`))
+	}
+
+	environment["SLACK_STR_CHANNEL"] = "user:" + fakeslack.UnknownEmail
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{"notify"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 4))
+	assert.Check(t, cmp.Contains(output, "unable to look up the user "+fakeslack.UnknownEmail+": users_not_found"))
+}
+
+func TestWorkspaces(t *testing.T) {
+	skip.If(t, testing.Short, "Test compiles and executes local binaries")
+
+	ctx := testcontext.Background()
+	fix := setupE2E(ctx, t)
+
+	slackAPIServer := httptest.NewServer(fix.slackAPI.Handler())
+	t.Cleanup(slackAPIServer.Close)
+	statusAPI := fakeslack.New(ctx)
+	statusAPIServer := httptest.NewServer(statusAPI.Handler())
+	t.Cleanup(statusAPIServer.Close)
+
+	environment := map[string]string{
+		"SLACK_ACCESS_TOKEN":        "test-token",
+		"SLACK_STR_WORKSPACES":      "status",
+		"SLACK_ACCESS_TOKEN_STATUS": "status-token",
+		"SLACK_API_BASE_URL_STATUS": statusAPIServer.URL,
+		"SLACK_STR_CHANNEL":         "test-channel,status:incidents",
+		"CCI_STATUS":                "pass",
+		"SLACK_STR_EVENT":           "always",
+		"SLACK_STR_OUTPUT":          "json",
+		"SLACK_STR_RESULT_FILE":     filepath.Join(t.TempDir(), "result.json"),
+	}
+
+	exitCode, output := fix.run(t, slackAPIServer.URL, []string{"notify"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, "Successfully posted message to channel: status:incidents"))
+	assert.Check(t, cmp.Contains(output, `"workspace": "status"`))
+	assert.Check(t, cmp.Equal(fix.slackAPI.LastRequest().Header.Get("Authorization"), "Bearer test-token"))
+	assert.Check(t, cmp.Equal(statusAPI.LastRequest().Header.Get("Authorization"), "Bearer status-token"))
+
+	exitCode, _ = fix.run(t, slackAPIServer.URL, []string{"delete"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, fix.slackAPI.Deleted("test-channel", "1700000000.000001"))
+	assert.Check(t, statusAPI.Deleted("incidents", "1700000000.000001"))
+
+	environment["SLACK_STR_CHANNEL"] = "eng:builds"
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{"notify"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 2))
+	assert.Check(t, cmp.Contains(output, `unknown workspace \"eng\"`))
+
+	delete(environment, "SLACK_ACCESS_TOKEN_STATUS")
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{"notify"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 2))
+	assert.Check(t, cmp.Contains(output, "SLACK_ACCESS_TOKEN_STATUS"))
+}
+
+func TestCABundle(t *testing.T) {
+	skip.If(t, testing.Short, "Test compiles and executes local binaries")
+
+	ctx := testcontext.Background()
+	fix := setupE2E(ctx, t)
+
+	slackAPIServer := httptest.NewTLSServer(fix.slackAPI.Handler())
+	t.Cleanup(slackAPIServer.Close)
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	err := os.WriteFile(caBundle, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: slackAPIServer.Certificate().Raw,
+	}), 0o600)
+	assert.NilError(t, err)
+
+	environment := map[string]string{
+		"SLACK_ACCESS_TOKEN":  "test-token",
+		"SLACK_STR_CHANNEL":   "test-channel",
+		"CCI_STATUS":          "pass",
+		"SLACK_STR_EVENT":     "always",
+		"SLACK_STR_CA_BUNDLE": caBundle,
+	}
+
+	exitCode, output := fix.run(t, slackAPIServer.URL, []string{"notify"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 0))
+	assert.Check(t, cmp.Contains(output, "Successfully posted message to channel: test-channel"))
+
+	environment["SLACK_STR_CA_BUNDLE"] = filepath.Join(t.TempDir(), "missing.pem")
+	exitCode, output = fix.run(t, slackAPIServer.URL, []string{"notify"}, environment)
+	assert.Check(t, cmp.Equal(exitCode, 2))
+	assert.Check(t, cmp.Contains(output, "unable to read the CA bundle"))
+}
+
+func TestTimeouts(t *testing.T) {
+	skip.If(t, testing.Short, "Test compiles and executes local binaries")
+
+	ctx := testcontext.Background()
+	fix := setupE2E(ctx, t)
+
+	slackAPIServer := httptest.NewServer(fix.slackAPI.Handler())
+	t.Cleanup(slackAPIServer.Close)
+
+	environment := map[string]string{
+		"SLACK_ACCESS_TOKEN": "test-token",
+		"SLACK_STR_CHANNEL":  "test-channel," + fakeslack.SlowChannel + ",other-channel",
+		"CCI_STATUS":         "pass",
+		"SLACK_STR_EVENT":    "always",
+		"SLACK_STR_OUTPUT":   "json",
+		"SLACK_STR_DEADLINE": "1s",
+	}
+
+	t.Run("deadline", func(t *testing.T) {
+		exitCode, output := fix.run(t, slackAPIServer.URL, []string{"notify"}, environment)
+		assert.Check(t, cmp.Equal(exitCode, 4))
+		assert.Check(t, cmp.Contains(output, "Successfully posted message to channel: test-channel"))
+		assert.Check(t, cmp.Contains(output, "abandoned sending the message to channel "+fakeslack.SlowChannel))
+		assert.Check(t, cmp.Contains(output, `"reason": "deadline_exceeded"`))
+		assert.Check(t, cmp.Contains(output, `"abandoned": true`))
+		assert.Check(t, !strings.Contains(output, "Successfully posted message to channel: other-channel"))
+	})
+
+	t.Run("request timeout", func(t *testing.T) {
+		delete(environment, "SLACK_STR_DEADLINE")
+		environment["SLACK_STR_REQUEST_TIMEOUT"] = "300ms"
+		environment["SLACK_BOOL_IGNORE_ERRORS"] = "true"
+
+		exitCode, output := fix.run(t, slackAPIServer.URL, []string{"notify"}, environment)
+		assert.Check(t, cmp.Equal(exitCode, 0))
+		assert.Check(t, cmp.Contains(output, "Successfully posted message to channel: test-channel"))
+		assert.Check(t, cmp.Contains(output, "Successfully posted message to channel: other-channel"))
+		assert.Check(t, !strings.Contains(output, `"abandoned": true`))
+	})
+}
+
 type e2eFixture struct {
 	slackOrbPath string
 	binariesDir  string
@@ -178,6 +896,36 @@ type e2eFixture struct {
 	slackAPI *fakeslack.API
 }
 
+// run executes the binary with args and environment against the Slack API at slackAPIURL,
+// in an empty directory, and returns its exit code and combined output.
+func (fix *e2eFixture) run(t *testing.T, slackAPIURL string, args []string, environment map[string]string) (int, string) {
+	t.Helper()
+
+	cmd := exec.Command(fix.slackOrbPath, args...)
+	// Run outside of this repository, so that its commits do not affect the notifications
+	cmd.Dir = t.TempDir()
+
+	comparableOutput := &strings.Builder{}
+	w := io.MultiWriter(os.Stdout, comparableOutput)
+	cmd.Stdout = w
+	cmd.Stderr = w
+	// Pin the CI provider, so that the CI system running the tests does not change the build
+	cmd.Env = append(cmd.Environ(), "TEST_SLACK_API_BASE_URL="+slackAPIURL, "SLACK_STR_CI_PROVIDER=circleci")
+	for key, value := range environment {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	assert.Assert(t, cmd.Start())
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+	})
+
+	// A non-zero exit code is reported through the process state
+	_ = cmd.Wait()
+
+	return cmd.ProcessState.ExitCode(), comparableOutput.String()
+}
+
 func setupE2E(ctx context.Context, t *testing.T) *e2eFixture {
 	slack := fakeslack.New(ctx)
 
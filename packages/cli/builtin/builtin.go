@@ -0,0 +1,49 @@
+// Package builtin computes the built-in SLACK_ORB_* variables available to templates.
+//
+// Each Provider derives a set of variables from the environment or from files such as test reports.
+package builtin
+
+import (
+	"fmt"
+	"maps"
+	"os"
+
+	"github.com/charmbracelet/log"
+)
+
+// Provider computes a set of built-in variables.
+type Provider interface {
+	// Name describes the provider in warnings.
+	Name() string
+	// Vars returns the variables derived from the environment, looked up with getenv.
+	Vars(getenv func(string) string) (map[string]string, error)
+}
+
+// Vars returns the variables of every provider. A provider that fails is skipped with a warning,
+// so that a missing report or an unexpected environment does not prevent the notification from being sent.
+func Vars(getenv func(string) string, providers ...Provider) map[string]string {
+	vars := map[string]string{}
+	for _, p := range providers {
+		pv, err := p.Vars(getenv)
+		if err != nil {
+			log.Warnf("Unable to compute the built-in %s variables: %v", p.Name(), err)
+			continue
+		}
+		maps.Copy(vars, pv)
+	}
+	return vars
+}
+
+// Set sets the variables of every provider in the environment. Variables that are already
+// set are left untouched, so that users can override them.
+func Set(providers ...Provider) error {
+	for name, value := range Vars(os.Getenv, providers...) {
+		if _, ok := os.LookupEnv(name); ok {
+			continue
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return fmt.Errorf("unable to set built-in env var %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package builtin
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+type fakeProvider struct {
+	vars map[string]string
+	err  error
+}
+
+func (f fakeProvider) Name() string {
+	return "fake"
+}
+
+func (f fakeProvider) Vars(_ func(string) string) (map[string]string, error) {
+	return f.vars, f.err
+}
+
+func TestVars(t *testing.T) {
+	vars := Vars(getenvFrom(nil),
+		fakeProvider{vars: map[string]string{"SLACK_ORB_ONE": "1"}},
+		fakeProvider{vars: map[string]string{"SLACK_ORB_TWO": "2"}, err: errors.New("broken")},
+		fakeProvider{vars: map[string]string{"SLACK_ORB_THREE": "3"}},
+	)
+	assert.Check(t, cmp.DeepEqual(vars, map[string]string{"SLACK_ORB_ONE": "1", "SLACK_ORB_THREE": "3"}))
+}
+
+func TestSet(t *testing.T) {
+	t.Setenv("SLACK_ORB_NEW", "")
+	os.Unsetenv("SLACK_ORB_NEW")
+	t.Setenv("SLACK_ORB_OVERRIDDEN", "user value")
+
+	err := Set(fakeProvider{vars: map[string]string{"SLACK_ORB_NEW": "new", "SLACK_ORB_OVERRIDDEN": "built-in"}})
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Equal(os.Getenv("SLACK_ORB_NEW"), "new"))
+	assert.Check(t, cmp.Equal(os.Getenv("SLACK_ORB_OVERRIDDEN"), "user value"))
+}
+
+func TestTimeVars(t *testing.T) {
+	now := func() time.Time { return time.Date(2024, time.January, 15, 10, 4, 12, 0, time.UTC) }
+	vars, err := Time{Format: "01/02/2006 15:04:05", Now: now}.Vars(getenvFrom(nil))
+	assert.NilError(t, err)
+	assert.Check(t, cmp.DeepEqual(vars, map[string]string{"SLACK_ORB_TIME_NOW": "01/15/2024 10:04:12"}))
+}
+
+func TestReportVars(t *testing.T) {
+	vars, err := TestResults{Path: "../junit/testdata/results.xml", MaxFailures: 5}.Vars(getenvFrom(nil))
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Equal(vars["SLACK_ORB_TESTS_FAILED"], "2"))
+
+	vars, err = Coverage{Path: "../coverage/testdata/lcov.info", Baseline: "missing.out", Threshold: 1}.Vars(getenvFrom(nil))
+	assert.NilError(t, err)
+	assert.Check(t, cmp.DeepEqual(vars, map[string]string{
+		"SLACK_ORB_COVERAGE":         "65.0%",
+		"SLACK_ORB_COVERAGE_SUMMARY": "Coverage is 65.0%",
+	}))
+
+	vars, err = TestResults{}.Vars(getenvFrom(nil))
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Len(vars, 0))
+}
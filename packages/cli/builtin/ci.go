@@ -0,0 +1,132 @@
+package builtin
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/ci"
+)
+
+const shortSHALength = 7
+
+// CI provides variables derived from the environment of the CI system, detected with ci.Current:
+//
+//   - SLACK_ORB_CI_PROVIDER: the name of the CI system, such as "circleci" or "github".
+//   - SLACK_ORB_BRANCH, SLACK_ORB_TAG, SLACK_ORB_COMMIT, SLACK_ORB_ACTOR, SLACK_ORB_BUILD_URL,
+//     SLACK_ORB_WORKFLOW_URL, SLACK_ORB_PROJECT and SLACK_ORB_JOB: the build, whatever the CI system.
+//   - SLACK_ORB_SHORT_SHA: the first characters of the commit.
+//   - SLACK_ORB_COMMIT_URL: the commit on GitHub, GitLab or Bitbucket, based on the repository URL.
+//   - SLACK_ORB_PR_NUMBER: the pull or merge request number.
+//   - SLACK_ORB_JOB_DURATION: the time since SLACK_ORB_JOB_STARTED_AT, a Unix or RFC3339 timestamp recorded
+//     by the user, or since the start of the job when the CI system exposes it, as GitLab does.
+//
+// Variables that cannot be derived are not set.
+type CI struct {
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+}
+
+func (c CI) Name() string {
+	return "CI"
+}
+
+func (c CI) Vars(getenv func(string) string) (map[string]string, error) {
+	build, err := ci.Current(getenv)
+	if err != nil {
+		return nil, err
+	}
+	vars := map[string]string{}
+	for name, value := range map[string]string{
+		"SLACK_ORB_CI_PROVIDER":  build.Provider,
+		"SLACK_ORB_BRANCH":       build.Branch,
+		"SLACK_ORB_TAG":          build.Tag,
+		"SLACK_ORB_COMMIT":       build.Commit,
+		"SLACK_ORB_ACTOR":        build.Actor,
+		"SLACK_ORB_BUILD_URL":    build.BuildURL,
+		"SLACK_ORB_WORKFLOW_URL": build.WorkflowURL,
+		"SLACK_ORB_PROJECT":      build.Project,
+		"SLACK_ORB_JOB":          build.Job,
+		"SLACK_ORB_PR_NUMBER":    build.PRNumber,
+	} {
+		if value != "" {
+			vars[name] = value
+		}
+	}
+
+	if sha := build.Commit; sha != "" {
+		vars["SLACK_ORB_SHORT_SHA"] = sha[:min(len(sha), shortSHALength)]
+		if commitURL := CommitURL(build.RepositoryURL, sha); commitURL != "" {
+			vars["SLACK_ORB_COMMIT_URL"] = commitURL
+		}
+	}
+
+	startedAt := getenv("SLACK_ORB_JOB_STARTED_AT")
+	if startedAt == "" {
+		startedAt = build.StartedAt
+	}
+	if startedAt != "" {
+		start, err := parseTimestamp(startedAt)
+		if err != nil {
+			// Keep the other variables, which do not depend on the start time
+			log.Warnf("Unable to compute SLACK_ORB_JOB_DURATION: %v", err)
+		} else {
+			now := time.Now
+			if c.Now != nil {
+				now = c.Now
+			}
+			vars["SLACK_ORB_JOB_DURATION"] = now().Sub(start).Round(time.Second).String()
+		}
+	}
+
+	return vars, nil
+}
+
+// scpLikeURL matches repository URLs such as "git@github.com:org/repo.git".
+var scpLikeURL = regexp.MustCompile(`^[\w.-]+@([\w.-]+):(.+)$`)
+
+// repository returns the host and the path, without the .git suffix, of a repository URL.
+func repository(repoURL string) (host, path string) {
+	if m := scpLikeURL.FindStringSubmatch(repoURL); m != nil {
+		host, path = m[1], m[2]
+	} else if u, err := url.Parse(repoURL); err == nil {
+		host, path = u.Hostname(), u.Path
+	}
+	return strings.ToLower(host), strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+}
+
+// CommitURL returns the URL of the commit sha in the repository at repoURL, or the empty string
+// when the repository is not hosted on GitHub, GitLab or Bitbucket.
+func CommitURL(repoURL, sha string) string {
+	host, path := repository(repoURL)
+	if host == "" || path == "" || sha == "" {
+		return ""
+	}
+
+	switch {
+	case strings.Contains(host, "github"):
+		return fmt.Sprintf("https://%s/%s/commit/%s", host, path, sha)
+	case strings.Contains(host, "gitlab"):
+		return fmt.Sprintf("https://%s/%s/-/commit/%s", host, path, sha)
+	case strings.Contains(host, "bitbucket"):
+		return fmt.Sprintf("https://%s/%s/commits/%s", host, path, sha)
+	default:
+		return ""
+	}
+}
+
+func parseTimestamp(value string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid job start time %q: must be a Unix or RFC3339 timestamp", value)
+	}
+	return t, nil
+}
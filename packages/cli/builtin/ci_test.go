@@ -0,0 +1,149 @@
+package builtin
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func getenvFrom(env map[string]string) func(string) string {
+	return func(name string) string { return env[name] }
+}
+
+func TestCIVars(t *testing.T) {
+	now := time.Date(2024, time.January, 15, 10, 4, 12, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected map[string]string
+	}{
+		{
+			name: "circleci pull request",
+			env: map[string]string{
+				"CIRCLECI":                 "true",
+				"CIRCLE_BRANCH":            "feature",
+				"CIRCLE_SHA1":              "0123456789abcdef0123456789abcdef01234567",
+				"CIRCLE_REPOSITORY_URL":    "git@github.com:CircleCI-Public/slack-orb-go.git",
+				"CIRCLE_PULL_REQUEST":      "https://github.com/CircleCI-Public/slack-orb-go/pull/123",
+				"SLACK_ORB_JOB_STARTED_AT": "1705312800", // 10:00:00
+			},
+			expected: map[string]string{
+				"SLACK_ORB_CI_PROVIDER":  "circleci",
+				"SLACK_ORB_BRANCH":       "feature",
+				"SLACK_ORB_COMMIT":       "0123456789abcdef0123456789abcdef01234567",
+				"SLACK_ORB_SHORT_SHA":    "0123456",
+				"SLACK_ORB_COMMIT_URL":   "https://github.com/CircleCI-Public/slack-orb-go/commit/0123456789abcdef0123456789abcdef01234567",
+				"SLACK_ORB_PR_NUMBER":    "123",
+				"SLACK_ORB_JOB_DURATION": "4m12s",
+			},
+		},
+		{
+			name: "forked pull request",
+			env: map[string]string{
+				"CIRCLE_PR_NUMBER": "42",
+			},
+			expected: map[string]string{
+				"SLACK_ORB_CI_PROVIDER": "circleci",
+				"SLACK_ORB_PR_NUMBER":   "42",
+			},
+		},
+		{
+			name: "github actions",
+			env: map[string]string{
+				"GITHUB_ACTIONS":    "true",
+				"GITHUB_SERVER_URL": "https://github.com",
+				"GITHUB_REPOSITORY": "org/repo",
+				"GITHUB_RUN_ID":     "1234",
+				"GITHUB_SHA":        "0123456789abcdef",
+				"GITHUB_ACTOR":      "jdoe",
+				"GITHUB_JOB":        "deploy",
+				"GITHUB_REF_NAME":   "v1.0.0",
+				"GITHUB_REF_TYPE":   "tag",
+			},
+			expected: map[string]string{
+				"SLACK_ORB_CI_PROVIDER":  "github",
+				"SLACK_ORB_TAG":          "v1.0.0",
+				"SLACK_ORB_COMMIT":       "0123456789abcdef",
+				"SLACK_ORB_SHORT_SHA":    "0123456",
+				"SLACK_ORB_COMMIT_URL":   "https://github.com/org/repo/commit/0123456789abcdef",
+				"SLACK_ORB_ACTOR":        "jdoe",
+				"SLACK_ORB_BUILD_URL":    "https://github.com/org/repo/actions/runs/1234",
+				"SLACK_ORB_WORKFLOW_URL": "https://github.com/org/repo/actions/runs/1234",
+				"SLACK_ORB_PROJECT":      "repo",
+				"SLACK_ORB_JOB":          "deploy",
+			},
+		},
+		{
+			name: "unknown host and RFC3339 start time",
+			env: map[string]string{
+				"CIRCLE_SHA1":              "abc",
+				"CIRCLE_REPOSITORY_URL":    "https://git.example.com/org/repo.git",
+				"SLACK_ORB_JOB_STARTED_AT": "2024-01-15T09:00:00Z",
+			},
+			expected: map[string]string{
+				"SLACK_ORB_CI_PROVIDER":  "circleci",
+				"SLACK_ORB_COMMIT":       "abc",
+				"SLACK_ORB_SHORT_SHA":    "abc",
+				"SLACK_ORB_JOB_DURATION": "1h4m12s",
+			},
+		},
+		{
+			name: "start time of the gitlab job",
+			env: map[string]string{
+				"GITLAB_CI":         "true",
+				"CI_JOB_STARTED_AT": "2024-01-15T10:00:00Z",
+			},
+			expected: map[string]string{
+				"SLACK_ORB_CI_PROVIDER":  "gitlab",
+				"SLACK_ORB_JOB_DURATION": "4m12s",
+			},
+		},
+		{
+			name: "invalid start time",
+			env: map[string]string{
+				"SLACK_ORB_JOB_STARTED_AT": "yesterday",
+			},
+			expected: map[string]string{"SLACK_ORB_CI_PROVIDER": "circleci"},
+		},
+		{
+			name:     "no environment",
+			env:      map[string]string{},
+			expected: map[string]string{"SLACK_ORB_CI_PROVIDER": "circleci"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars, err := CI{Now: func() time.Time { return now }}.Vars(getenvFrom(tt.env))
+			assert.NilError(t, err)
+			assert.Check(t, cmp.DeepEqual(vars, tt.expected))
+		})
+	}
+}
+
+func TestCommitURL(t *testing.T) {
+	const sha = "0123456789abcdef"
+
+	tests := []struct {
+		repoURL  string
+		expected string
+	}{
+		{repoURL: "git@github.com:org/repo.git", expected: "https://github.com/org/repo/commit/" + sha},
+		{repoURL: "https://github.com/org/repo", expected: "https://github.com/org/repo/commit/" + sha},
+		{repoURL: "ssh://git@github.example.com/org/repo.git", expected: "https://github.example.com/org/repo/commit/" + sha},
+		{repoURL: "git@gitlab.com:group/subgroup/repo.git", expected: "https://gitlab.com/group/subgroup/repo/-/commit/" + sha},
+		{repoURL: "https://bitbucket.org/org/repo.git", expected: "https://bitbucket.org/org/repo/commits/" + sha},
+		{repoURL: "git@bitbucket.org:org/repo.git", expected: "https://bitbucket.org/org/repo/commits/" + sha},
+		{repoURL: "https://git.example.com/org/repo.git", expected: ""},
+		{repoURL: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repoURL, func(t *testing.T) {
+			assert.Check(t, cmp.Equal(CommitURL(tt.repoURL, sha), tt.expected))
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package builtin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/ci"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/git"
+)
+
+// Separators passed to git log --format, which are unlikely to appear in commit messages.
+const (
+	fieldSeparator  = "\x1f"
+	commitSeparator = "\x1e"
+)
+
+var mrkdwnEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Git provides variables read from the git repository in Dir:
+//
+//   - SLACK_ORB_COMMIT_SUBJECT and SLACK_ORB_COMMIT_AUTHOR: the subject and author of HEAD.
+//   - SLACK_ORB_COMMIT_AUTHOR_EMAIL: the email address of the author of HEAD.
+//   - SLACK_ORB_COMMIT_MESSAGE: the full message of HEAD.
+//   - SLACK_ORB_CHANGELOG: a mrkdwn list of the commits between From and To, or between
+//     the tag before the tag being built and that tag.
+//   - SLACK_ORB_CHANGELOG_COUNT: the number of commits in the changelog.
+//
+// No variables are set outside of a git repository.
+type Git struct {
+	// Dir is the directory of the repository. It defaults to the working directory.
+	Dir string
+	// From and To are the revisions the changelog is made of. To defaults to HEAD.
+	From string
+	To   string
+	// MaxCommits is the number of commits listed in SLACK_ORB_CHANGELOG. Zero lists every commit.
+	MaxCommits int
+}
+
+type commit struct {
+	sha     string
+	subject string
+	author  string
+}
+
+func (g Git) Name() string {
+	return "git"
+}
+
+func (g Git) Vars(getenv func(string) string) (map[string]string, error) {
+	if !git.IsRepository(g.Dir) {
+		log.Debugf("Not reading commits, the directory is not a git repository")
+		return nil, nil
+	}
+
+	head, err := g.log("-1", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	vars := map[string]string{}
+	if len(head) == 1 {
+		vars["SLACK_ORB_COMMIT_SUBJECT"] = head[0].subject
+		vars["SLACK_ORB_COMMIT_AUTHOR"] = head[0].author
+	}
+	if vars["SLACK_ORB_COMMIT_AUTHOR_EMAIL"], err = g.git("log", "-1", "--format=%ae", "HEAD"); err != nil {
+		return nil, err
+	}
+	if vars["SLACK_ORB_COMMIT_MESSAGE"], err = g.git("log", "-1", "--format=%B", "HEAD"); err != nil {
+		return nil, err
+	}
+
+	build, err := ci.Current(getenv)
+	if err != nil {
+		return nil, err
+	}
+	revRange, err := g.changelogRange(build.Tag)
+	if err != nil || revRange == "" {
+		return vars, err
+	}
+	commits, err := g.log(revRange)
+	if err != nil {
+		return nil, err
+	}
+	vars["SLACK_ORB_CHANGELOG"] = changelog(commits, g.MaxCommits, build.RepositoryURL)
+	vars["SLACK_ORB_CHANGELOG_COUNT"] = strconv.Itoa(len(commits))
+	return vars, nil
+}
+
+// changelogRange returns the revision range of the changelog, or the empty string if there is none.
+func (g Git) changelogRange(tag string) (string, error) {
+	to := g.To
+	if to == "" {
+		to = "HEAD"
+	}
+	switch {
+	case g.From != "":
+		return g.From + ".." + to, nil
+	case tag != "":
+		previous, err := g.git("describe", "--tags", "--abbrev=0", tag+"^")
+		if err != nil {
+			// This is the first tag, so every commit up to it shipped
+			return tag, nil
+		}
+		return previous + ".." + tag, nil
+	default:
+		return "", nil
+	}
+}
+
+func (g Git) log(args ...string) ([]commit, error) {
+	format := "--format=" + strings.Join([]string{"%H", "%s", "%an"}, fieldSeparator) + commitSeparator
+	out, err := g.git(append([]string{"log", format}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []commit
+	for _, record := range strings.Split(out, commitSeparator) {
+		fields := strings.Split(strings.TrimSpace(record), fieldSeparator)
+		if len(fields) != 3 {
+			continue
+		}
+		commits = append(commits, commit{sha: fields[0], subject: fields[1], author: fields[2]})
+	}
+	return commits, nil
+}
+
+func (g Git) git(args ...string) (string, error) {
+	return git.Run(g.Dir, args...)
+}
+
+// changelog formats the commits as a mrkdwn list, linking them to the repository when possible.
+func changelog(commits []commit, maxCommits int, repoURL string) string {
+	if len(commits) == 0 {
+		return "No changes"
+	}
+
+	var b strings.Builder
+	for i, c := range commits {
+		if maxCommits > 0 && i == maxCommits {
+			fmt.Fprintf(&b, "…and %d more", len(commits)-maxCommits)
+			break
+		}
+		sha := c.sha[:min(len(c.sha), shortSHALength)]
+		if url := CommitURL(repoURL, c.sha); url != "" {
+			sha = fmt.Sprintf("<%s|%s>", url, sha)
+		}
+		fmt.Fprintf(&b, "• %s %s (%s)\n", sha, mrkdwnEscaper.Replace(c.subject), mrkdwnEscaper.Replace(c.author))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,97 @@
+package builtin
+
+import (
+	"os/exec"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+// newRepo creates a repository with a commit for every subject, and tags the commits in tags by index.
+func newRepo(t *testing.T, subjects []string, tags map[int]string) (string, []string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		out, err := Git{Dir: dir}.git(append([]string{
+			"-c", "user.name=Jane Doe", "-c", "user.email=jane@example.com", "-c", "commit.gpgsign=false", "-c", "tag.gpgsign=false",
+		}, args...)...)
+		assert.NilError(t, err)
+		return out
+	}
+
+	run("init", "--quiet")
+	var shas []string
+	for i, subject := range subjects {
+		run("commit", "--quiet", "--allow-empty", "-m", subject)
+		shas = append(shas, run("rev-parse", "HEAD"))
+		if tag, ok := tags[i]; ok {
+			run("tag", tag)
+		}
+	}
+	return dir, shas
+}
+
+func TestGitVars(t *testing.T) {
+	dir, shas := newRepo(t, []string{
+		"Initial commit",
+		"Add the deploy job",
+		"Fix <script> & escaping",
+		"Bump the version\n\nRelease v1.1.0 [slack:#releases]",
+	}, map[int]string{0: "v1.0.0", 3: "v1.1.0"})
+
+	t.Run("changelog since the previous tag", func(t *testing.T) {
+		env := map[string]string{"CIRCLE_TAG": "v1.1.0", "CIRCLE_REPOSITORY_URL": "git@github.com:org/repo.git"}
+		vars, err := Git{Dir: dir, MaxCommits: 2}.Vars(getenvFrom(env))
+		assert.NilError(t, err)
+		assert.Check(t, cmp.DeepEqual(vars, map[string]string{
+			"SLACK_ORB_COMMIT_SUBJECT":      "Bump the version",
+			"SLACK_ORB_COMMIT_AUTHOR":       "Jane Doe",
+			"SLACK_ORB_COMMIT_AUTHOR_EMAIL": "jane@example.com",
+			"SLACK_ORB_COMMIT_MESSAGE":      "Bump the version\n\nRelease v1.1.0 [slack:#releases]",
+			"SLACK_ORB_CHANGELOG_COUNT":     "3",
+			"SLACK_ORB_CHANGELOG": "• <https://github.com/org/repo/commit/" + shas[3] + "|" + shas[3][:7] + "> Bump the version (Jane Doe)\n" +
+				"• <https://github.com/org/repo/commit/" + shas[2] + "|" + shas[2][:7] + "> Fix &lt;script&gt; &amp; escaping (Jane Doe)\n" +
+				"…and 1 more",
+		}))
+	})
+
+	t.Run("first tag", func(t *testing.T) {
+		vars, err := Git{Dir: dir}.Vars(getenvFrom(map[string]string{"CIRCLE_TAG": "v1.0.0"}))
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(vars["SLACK_ORB_CHANGELOG"], "• "+shas[0][:7]+" Initial commit (Jane Doe)"))
+	})
+
+	t.Run("between two revisions", func(t *testing.T) {
+		vars, err := Git{Dir: dir, From: shas[0], To: shas[1]}.Vars(getenvFrom(nil))
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(vars["SLACK_ORB_CHANGELOG_COUNT"], "1"))
+	})
+
+	t.Run("without a tag", func(t *testing.T) {
+		vars, err := Git{Dir: dir}.Vars(getenvFrom(nil))
+		assert.NilError(t, err)
+		assert.Check(t, cmp.DeepEqual(vars, map[string]string{
+			"SLACK_ORB_COMMIT_SUBJECT":      "Bump the version",
+			"SLACK_ORB_COMMIT_AUTHOR":       "Jane Doe",
+			"SLACK_ORB_COMMIT_AUTHOR_EMAIL": "jane@example.com",
+			"SLACK_ORB_COMMIT_MESSAGE":      "Bump the version\n\nRelease v1.1.0 [slack:#releases]",
+		}))
+	})
+
+	t.Run("unknown revision", func(t *testing.T) {
+		_, err := Git{Dir: dir, From: "does-not-exist"}.Vars(getenvFrom(nil))
+		assert.Check(t, cmp.ErrorContains(err, "git log"))
+	})
+
+	t.Run("not a repository", func(t *testing.T) {
+		vars, err := Git{Dir: t.TempDir()}.Vars(getenvFrom(nil))
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Len(vars, 0))
+	})
+}
@@ -0,0 +1,80 @@
+package builtin
+
+import (
+	"github.com/charmbracelet/log"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/coverage"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/junit"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/summary"
+)
+
+// TestResults provides the SLACK_ORB_TESTS_* variables summarizing the JUnit reports at Path.
+type TestResults struct {
+	Path string
+	// MaxFailures is the number of failing tests listed in SLACK_ORB_TEST_FAILURES.
+	MaxFailures int
+}
+
+func (r TestResults) Name() string {
+	return "test results"
+}
+
+func (r TestResults) Vars(_ func(string) string) (map[string]string, error) {
+	if r.Path == "" {
+		return nil, nil
+	}
+	summary, err := junit.Load(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	return summary.EnvVars(r.MaxFailures), nil
+}
+
+// Coverage provides the SLACK_ORB_COVERAGE* variables read from the coverage report at Path.
+type Coverage struct {
+	Path string
+	// Baseline is the path of the report to compare with. It is optional.
+	Baseline string
+	// Threshold is the drop, in percentage points, from which the coverage is a regression.
+	Threshold float64
+}
+
+func (c Coverage) Name() string {
+	return "coverage"
+}
+
+func (c Coverage) Vars(_ func(string) string) (map[string]string, error) {
+	if c.Path == "" {
+		return nil, nil
+	}
+	summary := coverage.Summary{Threshold: c.Threshold}
+	var err error
+	if summary.Coverage, err = coverage.Load(c.Path); err != nil {
+		return nil, err
+	}
+	if c.Baseline != "" {
+		// The baseline may not exist yet, for example on the first build of the default branch
+		if summary.Baseline, err = coverage.Load(c.Baseline); err != nil {
+			log.Warnf("Unable to read the coverage baseline, the delta is not reported: %v", err)
+		} else {
+			summary.HasBaseline = true
+		}
+	}
+	return summary.EnvVars(), nil
+}
+
+// Workflow provides the SLACK_ORB_WORKFLOW_* variables summarizing the jobs recorded for the summarize command.
+type Workflow struct {
+	Summary summary.Summary
+}
+
+func (w Workflow) Name() string {
+	return "workflow summary"
+}
+
+func (w Workflow) Vars(_ func(string) string) (map[string]string, error) {
+	if len(w.Summary.Records) == 0 {
+		return nil, nil
+	}
+	return w.Summary.EnvVars(), nil
+}
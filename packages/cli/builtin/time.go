@@ -0,0 +1,22 @@
+package builtin
+
+import "time"
+
+// Time provides SLACK_ORB_TIME_NOW, the current time in Format.
+type Time struct {
+	Format string
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+}
+
+func (t Time) Name() string {
+	return "time"
+}
+
+func (t Time) Vars(_ func(string) string) (map[string]string, error) {
+	now := time.Now
+	if t.Now != nil {
+		now = t.Now
+	}
+	return map[string]string{"SLACK_ORB_TIME_NOW": now().Format(t.Format)}, nil
+}
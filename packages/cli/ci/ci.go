@@ -0,0 +1,102 @@
+// Package ci detects the CI system the CLI runs in and maps its environment into a common model.
+package ci
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Build describes the CI job the notification is sent for, independently of the CI system.
+type Build struct {
+	// Provider is the name of the CI system, such as "circleci".
+	Provider string
+
+	Branch string
+	Tag    string
+	// Status is "pass", "fail", or empty when it is unknown.
+	Status string
+
+	BuildURL string
+	// WorkflowURL links to the workflow or pipeline the job is part of.
+	WorkflowURL   string
+	Commit        string
+	Actor         string
+	RepositoryURL string
+	// PRNumber is the number of the pull or merge request, if the build is for one.
+	PRNumber string
+	Project  string
+	Job      string
+	// Node is the index of the parallel node running the job, when the job is split across nodes.
+	Node string
+	// StartedAt is the time the job started, as an RFC3339 timestamp, when the CI system exposes it.
+	StartedAt string
+}
+
+// Provider maps the environment of a CI system into a Build.
+type Provider interface {
+	// Name is the name used to select the provider with SLACK_STR_CI_PROVIDER.
+	Name() string
+	// Detect reports whether the environment is the one of this CI system.
+	Detect(getenv func(string) string) bool
+	Build(getenv func(string) string) Build
+}
+
+// Providers are the supported CI systems, in the order they are detected.
+var Providers = []Provider{GitHubActions{}, GitLab{}, CircleCI{}}
+
+// Detect returns the provider named by SLACK_STR_CI_PROVIDER, or an error when no provider has that name.
+// When the variable is empty, it returns the first provider that detects its environment, and falls back
+// to CircleCI, so that the variables of the orb keep working anywhere.
+func Detect(getenv func(string) string) (Provider, error) {
+	name := strings.ToLower(getenv("SLACK_STR_CI_PROVIDER"))
+	names := make([]string, 0, len(Providers))
+	for _, p := range Providers {
+		if name == p.Name() || (name == "" && p.Detect(getenv)) {
+			return p, nil
+		}
+		names = append(names, fmt.Sprintf("%q", p.Name()))
+	}
+	if name != "" {
+		return nil, fmt.Errorf("unknown CI provider %q in SLACK_STR_CI_PROVIDER, expected one of %s",
+			getenv("SLACK_STR_CI_PROVIDER"), strings.Join(names, ", "))
+	}
+	return CircleCI{}, nil
+}
+
+// Current returns the build of the detected provider. CCI_STATUS, which is written by the orb
+// and the record-status command, takes precedence over the status reported by the CI system.
+func Current(getenv func(string) string) (Build, error) {
+	provider, err := Detect(getenv)
+	if err != nil {
+		return Build{}, err
+	}
+	build := provider.Build(getenv)
+	if status := getenv("CCI_STATUS"); status != "" {
+		build.Status = NormalizeStatus(status)
+	}
+	return build, nil
+}
+
+// NormalizeStatus maps the job statuses of the CI systems to "pass" or "fail". Unknown statuses are
+// returned as they are, so that they are reported by the validation of the configuration.
+func NormalizeStatus(status string) string {
+	switch strings.ToLower(status) {
+	case "pass", "success", "succeeded", "passed":
+		return "pass"
+	case "fail", "failure", "failed", "canceled", "cancelled", "error":
+		return "fail"
+	default:
+		return status
+	}
+}
+
+// prNumber returns the number at the end of a pull request URL such as
+// "https://github.com/org/repo/pull/123", or the empty string if there is none.
+func prNumber(prURL string) string {
+	last := prURL[strings.LastIndex(prURL, "/")+1:]
+	if _, err := strconv.ParseUint(last, 10, 64); err != nil {
+		return ""
+	}
+	return last
+}
@@ -0,0 +1,235 @@
+package ci
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func getenvFrom(env map[string]string) func(string) string {
+	return func(name string) string { return env[name] }
+}
+
+func TestCurrent(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Build
+	}{
+		{
+			name: "circleci pull request",
+			env: map[string]string{
+				"CIRCLECI":                "true",
+				"CIRCLE_BRANCH":           "feature",
+				"CIRCLE_BUILD_URL":        "https://circleci.com/gh/org/repo/42",
+				"CIRCLE_SHA1":             "0123456789abcdef",
+				"CIRCLE_USERNAME":         "jdoe",
+				"CIRCLE_REPOSITORY_URL":   "git@github.com:org/repo.git",
+				"CIRCLE_PULL_REQUEST":     "https://github.com/org/repo/pull/123",
+				"CIRCLE_PROJECT_REPONAME": "repo",
+				"CIRCLE_JOB":              "test",
+				"CIRCLE_NODE_INDEX":       "1",
+				"CIRCLE_WORKFLOW_ID":      "fda08377-fe7e-46b1-8992-3a7aaecac9c3",
+				"CCI_STATUS":              "fail",
+			},
+			expected: Build{
+				Provider:      "circleci",
+				Branch:        "feature",
+				Status:        "fail",
+				BuildURL:      "https://circleci.com/gh/org/repo/42",
+				WorkflowURL:   "https://app.circleci.com/pipelines/workflows/fda08377-fe7e-46b1-8992-3a7aaecac9c3",
+				Commit:        "0123456789abcdef",
+				Actor:         "jdoe",
+				RepositoryURL: "git@github.com:org/repo.git",
+				PRNumber:      "123",
+				Project:       "repo",
+				Job:           "test",
+				Node:          "1",
+			},
+		},
+		{
+			name: "circleci forked pull request and tag",
+			env: map[string]string{
+				"CIRCLECI":         "true",
+				"CIRCLE_TAG":       "v1.0.0",
+				"CIRCLE_PR_NUMBER": "7",
+			},
+			expected: Build{Provider: "circleci", Tag: "v1.0.0", PRNumber: "7"},
+		},
+		{
+			name: "github actions pull request",
+			env: map[string]string{
+				"GITHUB_ACTIONS":       "true",
+				"GITHUB_SERVER_URL":    "https://github.com",
+				"GITHUB_REPOSITORY":    "org/repo",
+				"GITHUB_RUN_ID":        "1234",
+				"GITHUB_SHA":           "0123456789abcdef",
+				"GITHUB_ACTOR":         "jdoe",
+				"GITHUB_JOB":           "test",
+				"GITHUB_REF":           "refs/pull/123/merge",
+				"GITHUB_REF_NAME":      "123/merge",
+				"GITHUB_REF_TYPE":      "branch",
+				"GITHUB_HEAD_REF":      "feature",
+				"SLACK_STR_JOB_STATUS": "failure",
+			},
+			expected: Build{
+				Provider:      "github",
+				Branch:        "feature",
+				Status:        "fail",
+				BuildURL:      "https://github.com/org/repo/actions/runs/1234",
+				WorkflowURL:   "https://github.com/org/repo/actions/runs/1234",
+				Commit:        "0123456789abcdef",
+				Actor:         "jdoe",
+				RepositoryURL: "https://github.com/org/repo",
+				PRNumber:      "123",
+				Project:       "repo",
+				Job:           "test",
+			},
+		},
+		{
+			name: "github actions pull_request_target",
+			env: map[string]string{
+				"GITHUB_ACTIONS":       "true",
+				"GITHUB_SERVER_URL":    "https://github.com",
+				"GITHUB_REPOSITORY":    "org/repo",
+				"GITHUB_REF":           "refs/heads/main",
+				"GITHUB_REF_NAME":      "main",
+				"GITHUB_REF_TYPE":      "branch",
+				"GITHUB_HEAD_REF":      "feature",
+				"SLACK_STR_JOB_STATUS": "success",
+			},
+			expected: Build{
+				Provider:      "github",
+				Branch:        "feature",
+				Status:        "pass",
+				BuildURL:      "https://github.com/org/repo/actions/runs/",
+				WorkflowURL:   "https://github.com/org/repo/actions/runs/",
+				RepositoryURL: "https://github.com/org/repo",
+				Project:       "repo",
+			},
+		},
+		{
+			name: "github actions tag",
+			env: map[string]string{
+				"GITHUB_ACTIONS":       "true",
+				"GITHUB_SERVER_URL":    "https://github.com",
+				"GITHUB_REPOSITORY":    "org/repo",
+				"GITHUB_REF_NAME":      "v1.0.0",
+				"GITHUB_REF_TYPE":      "tag",
+				"SLACK_STR_JOB_STATUS": "success",
+			},
+			expected: Build{
+				Provider:      "github",
+				Tag:           "v1.0.0",
+				Status:        "pass",
+				BuildURL:      "https://github.com/org/repo/actions/runs/",
+				WorkflowURL:   "https://github.com/org/repo/actions/runs/",
+				RepositoryURL: "https://github.com/org/repo",
+				Project:       "repo",
+			},
+		},
+		{
+			name: "gitlab merge request",
+			env: map[string]string{
+				"GITLAB_CI":                           "true",
+				"CI_MERGE_REQUEST_SOURCE_BRANCH_NAME": "feature",
+				"CI_MERGE_REQUEST_IID":                "9",
+				"CI_JOB_STATUS":                       "success",
+				"CI_JOB_URL":                          "https://gitlab.com/group/repo/-/jobs/42",
+				"CI_PIPELINE_URL":                     "https://gitlab.com/group/repo/-/pipelines/7",
+				"CI_COMMIT_SHA":                       "0123456789abcdef",
+				"GITLAB_USER_LOGIN":                   "jdoe",
+				"CI_PROJECT_URL":                      "https://gitlab.com/group/repo",
+				"CI_PROJECT_NAME":                     "repo",
+				"CI_JOB_NAME":                         "test",
+				"CI_JOB_STARTED_AT":                   "2024-01-15T09:00:00Z",
+			},
+			expected: Build{
+				Provider:      "gitlab",
+				Branch:        "feature",
+				Status:        "pass",
+				BuildURL:      "https://gitlab.com/group/repo/-/jobs/42",
+				WorkflowURL:   "https://gitlab.com/group/repo/-/pipelines/7",
+				Commit:        "0123456789abcdef",
+				Actor:         "jdoe",
+				RepositoryURL: "https://gitlab.com/group/repo",
+				PRNumber:      "9",
+				Project:       "repo",
+				Job:           "test",
+				StartedAt:     "2024-01-15T09:00:00Z",
+			},
+		},
+		{
+			name: "CCI_STATUS takes precedence",
+			env: map[string]string{
+				"GITLAB_CI":     "true",
+				"CI_JOB_STATUS": "success",
+				"CCI_STATUS":    "fail",
+			},
+			expected: Build{Provider: "gitlab", Status: "fail"},
+		},
+		{
+			name: "provider selected explicitly",
+			env: map[string]string{
+				"GITHUB_ACTIONS":        "true",
+				"SLACK_STR_CI_PROVIDER": "GitLab",
+				"CI_COMMIT_BRANCH":      "main",
+			},
+			expected: Build{Provider: "gitlab", Branch: "main"},
+		},
+		{
+			name:     "unknown environment falls back to circleci",
+			env:      map[string]string{"CIRCLE_BRANCH": "main"},
+			expected: Build{Provider: "circleci", Branch: "main"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			build, err := Current(getenvFrom(tt.env))
+			assert.NilError(t, err)
+			assert.Check(t, cmp.DeepEqual(build, tt.expected))
+		})
+	}
+
+	t.Run("unknown provider", func(t *testing.T) {
+		_, err := Current(getenvFrom(map[string]string{
+			"CIRCLECI":              "true",
+			"SLACK_STR_CI_PROVIDER": "gitlab-ci",
+		}))
+		assert.Check(t, cmp.Error(err,
+			`unknown CI provider "gitlab-ci" in SLACK_STR_CI_PROVIDER, expected one of "github", "gitlab", "circleci"`))
+	})
+}
+
+func TestNormalizeStatus(t *testing.T) {
+	for status, expected := range map[string]string{
+		"pass":      "pass",
+		"success":   "pass",
+		"fail":      "fail",
+		"failure":   "fail",
+		"failed":    "fail",
+		"canceled":  "fail",
+		"cancelled": "fail",
+		"":          "",
+		"unknown":   "unknown",
+	} {
+		assert.Check(t, cmp.Equal(NormalizeStatus(status), expected), status)
+	}
+}
+
+func TestPRNumber(t *testing.T) {
+	assert.Check(t, cmp.Equal(prNumber("https://github.com/org/repo/pull/123"), "123"))
+	assert.Check(t, cmp.Equal(prNumber("https://bitbucket.org/org/repo/pull-requests/7"), "7"))
+	assert.Check(t, cmp.Equal(prNumber("https://github.com/org/repo/pull/"), ""))
+	assert.Check(t, cmp.Equal(prNumber(""), ""))
+}
+
+func TestPullRequestRefNumber(t *testing.T) {
+	assert.Check(t, cmp.Equal(pullRequestRefNumber("refs/pull/123/merge"), "123"))
+	assert.Check(t, cmp.Equal(pullRequestRefNumber("refs/pull/123/head"), "123"))
+	assert.Check(t, cmp.Equal(pullRequestRefNumber("refs/heads/main"), ""))
+	assert.Check(t, cmp.Equal(pullRequestRefNumber("refs/pull/feature/merge"), ""))
+	assert.Check(t, cmp.Equal(pullRequestRefNumber(""), ""))
+}
@@ -0,0 +1,41 @@
+package ci
+
+// CircleCI maps the environment of CircleCI jobs.
+type CircleCI struct{}
+
+func (CircleCI) Name() string {
+	return "circleci"
+}
+
+func (CircleCI) Detect(getenv func(string) string) bool {
+	return getenv("CIRCLECI") == "true"
+}
+
+func (CircleCI) Build(getenv func(string) string) Build {
+	pr := prNumber(getenv("CIRCLE_PULL_REQUEST"))
+	if pr == "" {
+		// Set instead of CIRCLE_PULL_REQUEST on pull requests from forks
+		pr = getenv("CIRCLE_PR_NUMBER")
+	}
+
+	var workflowURL string
+	if id := getenv("CIRCLE_WORKFLOW_ID"); id != "" {
+		workflowURL = "https://app.circleci.com/pipelines/workflows/" + id
+	}
+
+	return Build{
+		Provider:      "circleci",
+		Branch:        getenv("CIRCLE_BRANCH"),
+		Tag:           getenv("CIRCLE_TAG"),
+		Status:        NormalizeStatus(getenv("CCI_STATUS")),
+		BuildURL:      getenv("CIRCLE_BUILD_URL"),
+		WorkflowURL:   workflowURL,
+		Commit:        getenv("CIRCLE_SHA1"),
+		Actor:         getenv("CIRCLE_USERNAME"),
+		RepositoryURL: getenv("CIRCLE_REPOSITORY_URL"),
+		PRNumber:      pr,
+		Project:       getenv("CIRCLE_PROJECT_REPONAME"),
+		Job:           getenv("CIRCLE_JOB"),
+		Node:          getenv("CIRCLE_NODE_INDEX"),
+	}
+}
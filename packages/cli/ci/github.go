@@ -0,0 +1,62 @@
+package ci
+
+import (
+	"strconv"
+	"strings"
+)
+
+// GitHubActions maps the environment of GitHub Actions jobs. GitHub does not expose the job
+// status to steps, so it is read from SLACK_STR_JOB_STATUS, which is meant to be set to ${{ job.status }}.
+// Likewise, the index of a matrix job is read from SLACK_STR_JOB_INDEX, set to ${{ strategy.job-index }}.
+type GitHubActions struct{}
+
+func (GitHubActions) Name() string {
+	return "github"
+}
+
+func (GitHubActions) Detect(getenv func(string) string) bool {
+	return getenv("GITHUB_ACTIONS") == "true"
+}
+
+func (GitHubActions) Build(getenv func(string) string) Build {
+	repositoryURL := getenv("GITHUB_SERVER_URL") + "/" + getenv("GITHUB_REPOSITORY")
+	runURL := repositoryURL + "/actions/runs/" + getenv("GITHUB_RUN_ID")
+	build := Build{
+		Provider:      "github",
+		Status:        NormalizeStatus(getenv("SLACK_STR_JOB_STATUS")),
+		BuildURL:      runURL,
+		WorkflowURL:   runURL,
+		Commit:        getenv("GITHUB_SHA"),
+		Actor:         getenv("GITHUB_ACTOR"),
+		RepositoryURL: repositoryURL,
+		Project:       getenv("GITHUB_REPOSITORY")[strings.LastIndex(getenv("GITHUB_REPOSITORY"), "/")+1:],
+		Job:           getenv("GITHUB_JOB"),
+		Node:          getenv("SLACK_STR_JOB_INDEX"),
+	}
+
+	switch {
+	case getenv("GITHUB_HEAD_REF") != "":
+		build.Branch = getenv("GITHUB_HEAD_REF")
+		build.PRNumber = pullRequestRefNumber(getenv("GITHUB_REF"))
+	case getenv("GITHUB_REF_TYPE") == "tag":
+		build.Tag = getenv("GITHUB_REF_NAME")
+	default:
+		build.Branch = getenv("GITHUB_REF_NAME")
+	}
+	return build
+}
+
+// pullRequestRefNumber returns the number of a pull request ref such as "refs/pull/123/merge",
+// or the empty string for other refs. pull_request_target events run on the ref of the base
+// branch, such as "refs/heads/main".
+func pullRequestRefNumber(ref string) string {
+	rest, ok := strings.CutPrefix(ref, "refs/pull/")
+	if !ok {
+		return ""
+	}
+	number, _, _ := strings.Cut(rest, "/")
+	if _, err := strconv.ParseUint(number, 10, 64); err != nil {
+		return ""
+	}
+	return number
+}
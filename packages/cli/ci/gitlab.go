@@ -0,0 +1,36 @@
+package ci
+
+// GitLab maps the environment of GitLab CI jobs. The job status is only exposed in after_script.
+type GitLab struct{}
+
+func (GitLab) Name() string {
+	return "gitlab"
+}
+
+func (GitLab) Detect(getenv func(string) string) bool {
+	return getenv("GITLAB_CI") == "true"
+}
+
+func (GitLab) Build(getenv func(string) string) Build {
+	build := Build{
+		Provider:      "gitlab",
+		Branch:        getenv("CI_COMMIT_BRANCH"),
+		Tag:           getenv("CI_COMMIT_TAG"),
+		Status:        NormalizeStatus(getenv("CI_JOB_STATUS")),
+		BuildURL:      getenv("CI_JOB_URL"),
+		WorkflowURL:   getenv("CI_PIPELINE_URL"),
+		Commit:        getenv("CI_COMMIT_SHA"),
+		Actor:         getenv("GITLAB_USER_LOGIN"),
+		RepositoryURL: getenv("CI_PROJECT_URL"),
+		PRNumber:      getenv("CI_MERGE_REQUEST_IID"),
+		Project:       getenv("CI_PROJECT_NAME"),
+		Job:           getenv("CI_JOB_NAME"),
+		Node:          getenv("CI_NODE_INDEX"),
+		StartedAt:     getenv("CI_JOB_STARTED_AT"),
+	}
+	if build.Branch == "" {
+		// Merge request pipelines do not set CI_COMMIT_BRANCH
+		build.Branch = getenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME")
+	}
+	return build
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/result"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/slack"
+)
+
+// cancelScheduledCmd represents the cancel-scheduled command
+var cancelScheduledCmd = &cobra.Command{
+	Use:   "cancel-scheduled",
+	Short: "Cancel a scheduled slack notification",
+	Long: `Cancel messages scheduled with "notify --post-at" before they are posted.
+The messages are identified either by --channel and --scheduled-message-id,
+or by the result file written by "notify --result-file".`,
+	RunE: executeCancelScheduled,
+}
+
+func init() {
+	rootCmd.AddCommand(cancelScheduledCmd)
+
+	cancelScheduledCmd.Flags().String("channel", "", "The ID of the channel the message was scheduled in.")
+	viper.BindPFlag("cancel-scheduled.channel", cancelScheduledCmd.Flags().Lookup("channel"))
+	cancelScheduledCmd.Flags().String("scheduled-message-id", "", "The ID of the scheduled message to cancel.")
+	viper.BindPFlag("cancel-scheduled.scheduled-message-id", cancelScheduledCmd.Flags().Lookup("scheduled-message-id"))
+	cancelScheduledCmd.Flags().String("result-file", "", "Cancel every message scheduled in the result file written by notify.")
+	viper.BindPFlag("cancel-scheduled.result-file", cancelScheduledCmd.Flags().Lookup("result-file"))
+	viper.BindEnv("cancel-scheduled.result-file", "SLACK_STR_RESULT_FILE")
+}
+
+func executeCancelScheduled(_ *cobra.Command, _ []string) error {
+	targets, err := scheduledMessages(
+		viper.GetString("cancel-scheduled.channel"),
+		viper.GetString("cancel-scheduled.scheduled-message-id"),
+		viper.GetString("cancel-scheduled.result-file"),
+	)
+	if err != nil {
+		return withExitCode(ExitConfigError, err)
+	}
+	if len(targets) == 0 {
+		log.Infof("There are no scheduled messages to cancel.")
+		return nil
+	}
+
+	cfg, err := loadClientConfig()
+	if err != nil {
+		return err
+	}
+	clients, err := newSlackClients(cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, target := range targets {
+		client, err := clients.forWorkspace(target.Workspace)
+		if err != nil {
+			return withExitCode(ExitConfigError, err)
+		}
+		err = client.DeleteScheduledMessage(context.Background(), target.ChannelID, target.ScheduledMessageID)
+		switch {
+		case slack.IsAPIError(err, "invalid_scheduled_message_id"):
+			log.Warnf("The scheduled message %s in channel %s was already posted or cancelled.",
+				target.ScheduledMessageID, target.ChannelID)
+		case err != nil:
+			return withExitCode(ExitDeliveryError, fmt.Errorf("error cancelling scheduled message %s: %v",
+				target.ScheduledMessageID, err))
+		default:
+			log.Infof("Successfully cancelled scheduled message %s in channel: %s",
+				target.ScheduledMessageID, target.ChannelID)
+		}
+	}
+
+	return nil
+}
+
+// scheduledMessages returns the messages identified by the flags, either directly
+// by channel and scheduled message ID, or from a notify result file.
+func scheduledMessages(channel, scheduledMessageID, resultFile string) ([]result.ChannelResult, error) {
+	if channel != "" || scheduledMessageID != "" {
+		if channel == "" || scheduledMessageID == "" {
+			return nil, errors.New("both --channel and --scheduled-message-id must be provided")
+		}
+		return []result.ChannelResult{{ChannelID: channel, ScheduledMessageID: scheduledMessageID}}, nil
+	}
+
+	if resultFile == "" {
+		return nil, errors.New("either --result-file or --channel and --scheduled-message-id must be provided")
+	}
+	res, err := result.ReadFile(resultFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []result.ChannelResult
+	for _, channel := range res.Channels {
+		if channel.ScheduledMessageID != "" {
+			targets = append(targets, channel)
+		}
+	}
+	return targets, nil
+}
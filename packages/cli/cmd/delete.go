@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/result"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/slack"
+)
+
+// deleteCmd represents the delete command
+var deleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a posted slack notification",
+	Long: `Delete messages posted by notify, such as a "deploy started" message once the deploy was aborted.
+The messages are identified either by --channel and --ts, or by the result file written by "notify --result-file".
+
+With --older-than, only the messages posted longer ago than the duration are deleted.`,
+	Args: cobra.NoArgs,
+	RunE: executeDelete,
+}
+
+func init() {
+	rootCmd.AddCommand(deleteCmd)
+
+	deleteCmd.Flags().String("channel", "", "The ID of the channel the message was posted in.")
+	viper.BindPFlag("delete.channel", deleteCmd.Flags().Lookup("channel"))
+	deleteCmd.Flags().String("ts", "", "The timestamp of the message.")
+	viper.BindPFlag("delete.ts", deleteCmd.Flags().Lookup("ts"))
+	deleteCmd.Flags().String("result-file", "", "Delete every message posted in the result file written by notify.")
+	viper.BindPFlag("delete.result-file", deleteCmd.Flags().Lookup("result-file"))
+	viper.BindEnv("delete.result-file", "SLACK_STR_RESULT_FILE")
+	deleteCmd.Flags().Duration("older-than", 0, "Only delete the messages posted longer ago than this duration, such as 30m.")
+	viper.BindPFlag("delete.older-than", deleteCmd.Flags().Lookup("older-than"))
+	deleteCmd.Flags().String("output", "text", `Set the output format. Use "json" to print a JSON result document to stdout.`)
+	viper.BindPFlag("delete.output", deleteCmd.Flags().Lookup("output"))
+	viper.BindEnv("delete.output", "SLACK_STR_OUTPUT")
+}
+
+func executeDelete(_ *cobra.Command, _ []string) error {
+	targets, err := postedMessages(
+		viper.GetString("delete.channel"),
+		viper.GetString("delete.ts"),
+		viper.GetString("delete.result-file"),
+	)
+	if err != nil {
+		return withExitCode(ExitConfigError, err)
+	}
+	if len(targets) == 0 {
+		log.Infof("There are no posted messages to delete.")
+		return nil
+	}
+
+	cfg, err := loadClientConfig()
+	if err != nil {
+		return err
+	}
+	clients, err := newSlackClients(cfg)
+	if err != nil {
+		return err
+	}
+
+	olderThan := viper.GetDuration("delete.older-than")
+	now := time.Now()
+	res := result.New()
+	for _, target := range targets {
+		client, err := clients.forWorkspace(target.Workspace)
+		if err != nil {
+			return withExitCode(ExitConfigError, err)
+		}
+		res.AddDeletion(deleteMessage(context.Background(), client, target, olderThan, now))
+	}
+	emitResult(res, viper.GetString("delete.output"), "")
+
+	if res.Decision == result.DecisionFailed {
+		return withExitCode(ExitDeliveryError, errors.New("some messages could not be deleted"))
+	}
+	return nil
+}
+
+// deleteMessage deletes the message of target, unless it was posted less than olderThan before now.
+// A message that was already deleted is not an error.
+func deleteMessage(
+	ctx context.Context, client *slack.Client, target result.ChannelResult, olderThan time.Duration, now time.Time,
+) result.ChannelResult {
+	channelResult := result.ChannelResult{
+		Channel: target.Channel, Workspace: target.Workspace, ChannelID: target.ChannelID, TS: target.TS,
+	}
+	if channelResult.Channel == "" {
+		channelResult.Channel = target.ChannelID
+	}
+
+	if olderThan > 0 {
+		posted, err := slack.MessageTime(target.TS)
+		if err != nil {
+			channelResult.Error = err.Error()
+			log.Errorf("Unable to delete message %s in channel %s: %v", target.TS, target.ChannelID, err)
+			return channelResult
+		}
+		if now.Sub(posted) < olderThan {
+			channelResult.Reason = result.ReasonTooRecent
+			log.Infof("Not deleting message %s in channel %s: it was posted less than %s ago.",
+				target.TS, target.ChannelID, olderThan)
+			return channelResult
+		}
+	}
+
+	err := client.DeleteMessage(ctx, target.ChannelID, target.TS)
+	switch {
+	case slack.IsAPIError(err, "message_not_found"):
+		channelResult.Reason = result.ReasonAlreadyDeleted
+		log.Warnf("The message %s in channel %s was already deleted.", target.TS, target.ChannelID)
+	case err != nil:
+		channelResult.Error = err.Error()
+		log.Errorf("Unable to delete message %s in channel %s: %v", target.TS, target.ChannelID, err)
+	default:
+		channelResult.Deleted = true
+		log.Infof("Successfully deleted message %s in channel: %s", target.TS, target.ChannelID)
+	}
+	return channelResult
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Exit codes returned by the CLI. They are part of the public interface of the
+// binary, so wrapper scripts can tell configuration problems apart from Slack outages.
+const (
+	// ExitOK is returned when the notification was posted, or skipped without
+	// a custom skip exit code.
+	ExitOK = 0
+	// ExitError is returned for errors that do not fit any other category, such as invalid flags.
+	ExitError = 1
+	// ExitConfigError is returned when the configuration is missing or invalid.
+	ExitConfigError = 2
+	// ExitRenderError is returned when the message template cannot be found or rendered.
+	ExitRenderError = 3
+	// ExitDeliveryError is returned when the message could not be delivered to Slack.
+	ExitDeliveryError = 4
+)
+
+// exitError carries the exit code the process should terminate with.
+// A nil err means the command already reported everything it needed to.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit code %d", e.code)
+	}
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+// exitCode returns the exit code for an error returned by a command.
+func exitCode(err error) int {
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return ExitError
+}
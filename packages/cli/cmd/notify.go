@@ -3,51 +3,394 @@ package cmd
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/log"
-	"github.com/circleci/ex/config/secret"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/ci"
 	"github.com/CircleCI-Public/slack-orb-go/packages/cli/config"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/dedupe"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/git"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/jobstatus"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/notifier"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/result"
 	"github.com/CircleCI-Public/slack-orb-go/packages/cli/slack"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/summary"
 	"github.com/CircleCI-Public/slack-orb-go/packages/cli/utils"
 )
 
 // notifyCmd represents the notify command
 var notifyCmd = &cobra.Command{
-	Use:   "notify",
-	Short: "Send a slack notification",
-	Long:  `Send a custom notification to slack`,
-	Run:   executeNotify,
+	Use:     "notify",
+	Short:   "Send a slack notification",
+	Long:    `Send a custom notification to slack`,
+	PreRunE: bindNotifyFlags,
+	RunE:    executeNotify,
 }
 
 func init() {
 	rootCmd.AddCommand(notifyCmd)
 
 	viper.AutomaticEnv()
+	addNotifyFlags(notifyCmd)
+	// Other commands load the configuration too, and rely on the defaults of the flags
+	_ = bindNotifyFlags(notifyCmd, nil)
+}
+
+// addNotifyFlags registers the flags of notify on cmd, such as summarize, and binds their
+// environment variables. As the commands share the viper keys, the flags of the command being
+// run are bound to them by bindNotifyFlags.
+func addNotifyFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+
 	// Add time format
-	notifyCmd.Flags().String("time-format", "01/02/2006 15:04:05", "Set the built-in $SLACK_ORB_TIME_NOW variable to the provided format. Must be in the format of a Go time.Format string.")
-	viper.BindPFlag("time-format", notifyCmd.Flags().Lookup("time-format"))
+	flags.String("time-format", "01/02/2006 15:04:05", "Set the built-in $SLACK_ORB_TIME_NOW variable to the provided format. Must be in the format of a Go time.Format string.")
 	viper.BindEnv("time-format", "SLACK_ORB_TIME_FORMAT")
 
+	// Add test results summary
+	flags.String("test-results", "", "Summarize the JUnit XML reports at the provided file, directory or glob pattern in the built-in $SLACK_ORB_TESTS_* variables.")
+	viper.BindEnv("test-results", "SLACK_STR_TEST_RESULTS")
+	flags.Int("test-failures-limit", 5, "Set the number of failing tests listed in the built-in $SLACK_ORB_TEST_FAILURES variable.")
+	viper.BindEnv("test-failures-limit", "SLACK_INT_TEST_FAILURES_LIMIT")
+
+	// Add commit message directives
+	flags.String("commit-message", "", "Check the provided commit message for the [skip slack] and [slack:#channel] directives. Defaults to the message of the HEAD commit.")
+	viper.BindEnv("commit-message", "SLACK_STR_COMMIT_MESSAGE", "SLACK_ORB_COMMIT_MESSAGE")
+	flags.String("commit-channel-mode", slack.CommitChannelModeAppend, `Set whether the channels requested with [slack:#channel] are added to the configured channels ("append") or replace them ("replace").`)
+	viper.BindEnv("commit-channel-mode", "SLACK_STR_COMMIT_CHANNEL_MODE")
+
+	// Add changed paths filter
+	flags.StringArray("path-pattern", nil, `Only notify when a file matching the provided glob pattern, such as "services/api/**", changed since --path-base. Can be repeated.`)
+	viper.BindEnv("path-pattern", "SLACK_STR_PATH_PATTERN")
+	flags.String("path-base", "HEAD~1", `Set the revision, such as "origin/main", the changed files are computed against. It must be in the fetched history: the path patterns are ignored when it is not, such as in a shallow clone.`)
+	viper.BindEnv("path-base", "SLACK_STR_PATH_BASE")
+
+	// Add dry run
+	flags.Bool("dry-run", false, "Render the message and evaluate the conditions without sending anything.")
+	viper.BindEnv("dry-run", "SLACK_BOOL_DRY_RUN")
+
+	// Add changelog
+	flags.String("changelog-from", "", "List the commits after the provided revision in the built-in $SLACK_ORB_CHANGELOG variable. Defaults to the tag before $CIRCLE_TAG.")
+	viper.BindEnv("changelog-from", "SLACK_STR_CHANGELOG_FROM")
+	flags.String("changelog-to", "", "List the commits up to the provided revision in the built-in $SLACK_ORB_CHANGELOG variable. Defaults to HEAD.")
+	viper.BindEnv("changelog-to", "SLACK_STR_CHANGELOG_TO")
+	flags.Int("changelog-limit", 10, "Set the number of commits listed in the built-in $SLACK_ORB_CHANGELOG variable.")
+	viper.BindEnv("changelog-limit", "SLACK_INT_CHANGELOG_LIMIT")
+
+	// Add coverage summary
+	flags.String("coverage", "", "Read the coverage from the provided Go cover profile, Cobertura XML or LCOV report into the built-in $SLACK_ORB_COVERAGE variable.")
+	viper.BindEnv("coverage", "SLACK_STR_COVERAGE")
+	flags.String("coverage-baseline", "", "Compare the coverage with the provided report, such as one from the default branch, in the built-in $SLACK_ORB_COVERAGE_DELTA variable.")
+	viper.BindEnv("coverage-baseline", "SLACK_STR_COVERAGE_BASELINE")
+	flags.Float64("coverage-threshold", 1, "Highlight coverage drops larger than the provided number of percentage points as regressions.")
+	viper.BindEnv("coverage-threshold", "SLACK_STR_COVERAGE_THRESHOLD")
+
+	// Add machine-readable result output
+	flags.String("output", "text", `Set the output format. Use "json" to print a JSON result document to stdout.`)
+	viper.BindEnv("output", "SLACK_STR_OUTPUT")
+	flags.String("result-file", "", "Write a JSON result document to the provided path.")
+	viper.BindEnv("result-file", "SLACK_STR_RESULT_FILE")
+
+	// Add exit code for skipped notifications
+	flags.Int("skip-exit-code", ExitOK, "Exit with the provided code whenever the notification is skipped: the job status or the branch and tag filters do not match, it is outside the delivery window, the commit message contains [skip slack], no changed file matches the path patterns, it is a duplicate or its user is unknown. The reason is reported in the result output. Must be 0 or between 5 and 255, as codes 1 to 4 are used for errors.")
+	viper.BindEnv("skip-exit-code", "SLACK_INT_SKIP_EXIT_CODE")
+
+	// Add scheduled delivery
+	flags.String("post-at", "", `Schedule the message instead of posting it right away. Accepts a duration such as "2h", or a time such as "09:00", "2006-01-02 09:00" or an RFC3339 timestamp.`)
+	viper.BindEnv("post-at", "SLACK_STR_POST_AT")
+	flags.String("timezone", "", `Set the IANA time zone, such as "Europe/Berlin", used to interpret --post-at. Defaults to the local time zone.`)
+	viper.BindEnv("timezone", "SLACK_STR_TIMEZONE")
+
+	// Add delivery window
+	flags.String("delivery-window", "", `Only deliver notifications during the provided window, such as "Mon-Fri 08:00-19:00". Interpreted in --timezone.`)
+	viper.BindEnv("delivery-window", "SLACK_STR_DELIVERY_WINDOW")
+	flags.String("delivery-window-policy", slack.DeliveryWindowPolicySkip, `Set what happens to notifications outside the delivery window: "skip" them, "defer" them to the start of the next window, or let "failures-only" through.`)
+	viper.BindEnv("delivery-window-policy", "SLACK_STR_DELIVERY_WINDOW_POLICY")
+
+	// Add file attachments
+	flags.StringArray("attach", nil, `Upload the files matching the provided path or glob pattern, such as "test-results/*.xml", in the thread of the message. Can be repeated.`)
+	viper.BindEnv("attach", "SLACK_STR_ATTACH")
+	flags.String("attach-max-size", "10MB", `Skip attachments larger than the provided size, such as "512KB" or "10MB".`)
+	viper.BindEnv("attach-max-size", "SLACK_STR_ATTACH_MAX_SIZE")
+
+	// Add workflow summary records
+	flags.Bool("record-only", false, "Record the status, duration and link of the job for the summarize command instead of notifying.")
+	viper.BindEnv("record-only", "SLACK_BOOL_RECORD_ONLY")
+	flags.String("record-dir", "/tmp/slack-orb/jobs", "Set the directory the job records are written to and read from. Share it between jobs with a workspace.")
+	viper.BindEnv("record-dir", "SLACK_STR_RECORD_DIR")
+
+	// Add deduplication
+	flags.String("dedupe-window", "", `Handle notifications repeated within the provided duration, such as "1h", according to --dedupe-policy. Disabled by default.`)
+	viper.BindEnv("dedupe-window", "SLACK_STR_DEDUPE_WINDOW")
+	flags.String("dedupe-key", "", "Set the key identifying repeated notifications. Defaults to the project, branch, job and status.")
+	viper.BindEnv("dedupe-key", "SLACK_STR_DEDUPE_KEY")
+	flags.String("dedupe-policy", dedupe.PolicySuppress, `Set what happens to repeated notifications: "suppress" them, or "thread" them under the first one and update its counter.`)
+	viper.BindEnv("dedupe-policy", "SLACK_STR_DEDUPE_POLICY")
+	flags.String("dedupe-state", "/tmp/slack-orb/dedupe.json", "Set the file remembering the notifications that were sent. Persist it across jobs with a cache or a workspace.")
+	viper.BindEnv("dedupe-state", "SLACK_STR_DEDUPE_STATE")
+
+	// Add ephemeral messages
+	flags.Bool("ephemeral", false, "Only show the message to the Slack user mapped from the user who triggered the build, or send it to them as a direct message.")
+	viper.BindEnv("ephemeral", "SLACK_BOOL_EPHEMERAL")
+	flags.String("user-map", "", `Map the users of the CI provider to Slack user IDs, such as "jdoe=U0123456789,asmith=U9876543210".`)
+	viper.BindEnv("user-map", "SLACK_STR_USER_MAP")
+
+	// Add overall deadline
+	flags.Duration("deadline", 0, `Abandon the channels the message was not sent to after the provided duration, such as "2m". Disabled by default.`)
+	viper.BindEnv("deadline", "SLACK_STR_DEADLINE")
+}
+
+// bindNotifyFlags binds the flags registered by addNotifyFlags on the command being run to viper.
+func bindNotifyFlags(cmd *cobra.Command, _ []string) error {
+	return viper.BindPFlags(cmd.LocalNonPersistentFlags())
+}
+
+func executeNotify(_ *cobra.Command, _ []string) error {
+	output := viper.GetString("output")
+	if output != "text" && output != "json" {
+		return withExitCode(ExitConfigError, fmt.Errorf("invalid value for --output: %q. Must be one of \"text\" or \"json\"", output))
+	}
+	resultFile := viper.GetString("result-file")
+	skipExitCode := viper.GetInt("skip-exit-code")
+	if skipExitCode < 0 || skipExitCode > 255 {
+		return withExitCode(ExitConfigError, fmt.Errorf("invalid value for --skip-exit-code: %d. Must be between 0 and 255", skipExitCode))
+	}
+	if skipExitCode > ExitOK && skipExitCode <= ExitDeliveryError {
+		return withExitCode(ExitConfigError, fmt.Errorf("invalid value for --skip-exit-code: %d. Codes 1 to %d are used for errors", skipExitCode, ExitDeliveryError))
+	}
+	if viper.GetBool("record-only") {
+		return recordJob()
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	notifierConfig := newNotifierConfig(cfg)
+	// Permalinks cost an extra API call per channel, so only look them up when a result is requested.
+	notifierConfig.Permalinks = output == "json" || resultFile != ""
+	notifierConfig.PostAt, notifierConfig.DeliveryWindow, err = deliveryTime()
+	if err != nil {
+		return withExitCode(ExitConfigError, err)
+	}
+	notifierConfig.DeliveryWindowPolicy = viper.GetString("delivery-window-policy")
+	notifierConfig.CommitMessage = viper.GetString("commit-message")
+	notifierConfig.CommitChannelMode = viper.GetString("commit-channel-mode")
+	notifierConfig.PathPatterns, notifierConfig.ChangedFiles, err = changedFiles(splitList(viper.GetStringSlice("path-pattern")))
+	if err != nil {
+		return withExitCode(ExitConfigError, err)
+	}
+	notifierConfig.DryRun = viper.GetBool("dry-run")
+	notifierConfig.Attachments = splitList(viper.GetStringSlice("attach"))
+	notifierConfig.MaxAttachmentSize, err = utils.ParseByteSize(viper.GetString("attach-max-size"))
+	if err != nil {
+		return withExitCode(ExitConfigError, fmt.Errorf("invalid value for --attach-max-size: %w", err))
+	}
+	if err := setDedupe(&notifierConfig); err != nil {
+		return withExitCode(ExitConfigError, err)
+	}
+	if err := setEphemeral(&notifierConfig); err != nil {
+		return withExitCode(ExitConfigError, err)
+	}
+
+	clients, err := newSlackClients(cfg)
+	if err != nil {
+		return err
+	}
+
+	n := notifier.New(notifierConfig, notifier.Options{
+		Sender:     clients.defaultClient,
+		Workspaces: clients.senders(),
+		Logger:     log.Default(),
+	})
+	ctx := context.Background()
+	if deadline := viper.GetDuration("deadline"); deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, deadline)
+		defer cancel()
+	}
+	res, err := n.Notify(ctx)
+	emitResult(res, output, resultFile)
+
+	return handleNotifyError(err, notifierConfig, skipExitCode)
 }
 
-func executeNotify(_ *cobra.Command, _ []string) {
-	cfg := config.SlackConfig
-	channels := strings.Split(cfg.Channels, ",")
+// recordJob writes the outcome of the job to the record directory for the summarize command.
+func recordJob() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return withExitCode(ExitConfigError, fmt.Errorf("error loading environment configuration: \n%v", err))
+	}
+	if err := jobstatus.Validate(cfg.JobStatus); err != nil {
+		return withExitCode(ExitConfigError, err)
+	}
+
+	build, err := ci.Current(os.Getenv)
+	if err != nil {
+		return withExitCode(ExitConfigError, err)
+	}
+	path, err := summary.WriteRecord(viper.GetString("record-dir"), summary.Record{
+		Job:        build.Job,
+		Node:       build.Node,
+		Status:     cfg.JobStatus,
+		Duration:   os.Getenv("SLACK_ORB_JOB_DURATION"),
+		URL:        build.BuildURL,
+		RecordedAt: time.Now(),
+	})
+	if err != nil {
+		return withExitCode(ExitConfigError, err)
+	}
+	log.Infof("Recorded the status %q of job %s in %s", cfg.JobStatus, build.Job, path)
+	return nil
+}
 
+// deliveryTime returns the time the message should be scheduled at, or the zero time to post it
+// right away, and the delivery window if one is configured.
+func deliveryTime() (time.Time, *slack.DeliveryWindow, error) {
+	loc, err := utils.LoadLocation(viper.GetString("timezone"))
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	postAt, err := utils.ParsePostAt(viper.GetString("post-at"), time.Now(), loc)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	spec := viper.GetString("delivery-window")
+	if spec == "" {
+		return postAt, nil, nil
+	}
+	window, err := slack.ParseDeliveryWindow(spec, loc)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	return postAt, window, nil
+}
+
+// splitList splits comma separated values, such as the ones of the SLACK_STR_ATTACH variable.
+// Values passed with repeated flags are split as well.
+func splitList(values []string) []string {
+	var items []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// changedFiles returns the path patterns and the files changed since the base revision, when path patterns
+// are configured. When the base revision is not in the history of the checkout, such as in a shallow clone,
+// the changed files cannot be determined and no pattern is returned, so that the notification is sent.
+func changedFiles(patterns []string) ([]string, []string, error) {
+	if len(patterns) == 0 {
+		return nil, nil, nil
+	}
+	base := viper.GetString("path-base")
+	if git.IsRepository("") && !git.HasRevision("", base) {
+		log.Warnf("Unable to determine the changed files: the --path-base revision %q is not in the history of the checkout, such as in a shallow clone. "+
+			"Fetch more history or set --path-base to a fetched revision. The path patterns are ignored.", base)
+		return nil, nil, nil
+	}
+	files, err := git.ChangedFiles("", base)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to list the files changed since %q: %w", base, err)
+	}
+	return patterns, files, nil
+}
+
+// setDedupe configures the deduplication of repeated notifications when a dedupe window is set.
+func setDedupe(cfg *notifier.Config) error {
+	spec := viper.GetString("dedupe-window")
+	if spec == "" {
+		return nil
+	}
+	window, err := time.ParseDuration(spec)
+	if err != nil {
+		return fmt.Errorf("invalid value for --dedupe-window: %w", err)
+	}
+
+	key := os.ExpandEnv(viper.GetString("dedupe-key"))
+	if key == "" {
+		build, err := ci.Current(os.Getenv)
+		if err != nil {
+			return err
+		}
+		key = dedupe.Key(build.Project, build.Branch, build.Job, cfg.Status)
+	}
+
+	cfg.DedupeStore = dedupe.FileStore{Path: viper.GetString("dedupe-state")}
+	cfg.DedupeKey = key
+	cfg.DedupeWindow = window
+	cfg.DedupePolicy = viper.GetString("dedupe-policy")
+	return nil
+}
+
+// setEphemeral shows the message only to the Slack user mapped from the user who triggered the build,
+// such as $CIRCLE_USERNAME, when ephemeral messages are enabled.
+func setEphemeral(cfg *notifier.Config) error {
+	if !viper.GetBool("ephemeral") {
+		return nil
+	}
+	users, err := parseUserMap(viper.GetString("user-map"))
+	if err != nil {
+		return err
+	}
+	build, err := ci.Current(os.Getenv)
+	if err != nil {
+		return err
+	}
+	cfg.Ephemeral = true
+	actor := build.Actor
+	if user, ok := users[actor]; ok {
+		cfg.EphemeralUser = user
+		return nil
+	}
+
+	// The notifier falls back to a direct message to the author of the commit, or skips the notification
+	if actor == "" {
+		log.Warnf("The user who triggered the build is unknown")
+	} else {
+		log.Warnf("No Slack user is mapped to %q in --user-map", actor)
+	}
+	cfg.EphemeralEmail = os.Getenv("SLACK_ORB_COMMIT_AUTHOR_EMAIL")
+	return nil
+}
+
+// parseUserMap parses a comma separated list of "<CI user>=<Slack user ID>" pairs.
+func parseUserMap(spec string) (map[string]string, error) {
+	users := map[string]string{}
+	for _, pair := range splitList([]string{spec}) {
+		name, id, ok := strings.Cut(pair, "=")
+		name, id = strings.TrimSpace(name), strings.TrimSpace(id)
+		if !ok || name == "" || id == "" {
+			return nil, fmt.Errorf("invalid --user-map entry %q, expected <user>=<Slack user ID>", pair)
+		}
+		users[name] = id
+	}
+	return users, nil
+}
+
+// newNotifierConfig maps the configuration loaded from the environment to the notifier configuration.
+func newNotifierConfig(cfg *config.Config) notifier.Config {
 	invertMatch, _ := strconv.ParseBool(cfg.InvertMatch) // will default to false on a parse error
 	ignoreErrors, _ := strconv.ParseBool(cfg.IgnoreErrors)
 
-	slackNotification := slack.Notification{
+	return notifier.Config{
+		Channels:       strings.Split(cfg.Channels, ","),
 		Status:         cfg.JobStatus,
+		Event:          cfg.EventToSendMessage,
 		Branch:         cfg.JobBranch,
 		Tag:            cfg.JobTag,
-		Event:          cfg.EventToSendMessage,
 		BranchPattern:  cfg.BranchPattern,
 		TagPattern:     cfg.TagPattern,
 		InvertMatch:    invertMatch,
@@ -55,44 +398,80 @@ func executeNotify(_ *cobra.Command, _ []string) {
 		TemplatePath:   cfg.TemplatePath,
 		TemplateInline: cfg.TemplateInline,
 		TemplateName:   cfg.TemplateName,
+		IgnoreErrors:   ignoreErrors,
 	}
+}
 
-	modifiedJSON, err := slackNotification.BuildMessageBody()
-	if err != nil {
-		if errors.Is(err, slack.ErrStatusMismatch) {
+// handleNotifyError logs the outcome of a failed or skipped notification
+// and returns the error carrying the matching exit code.
+func handleNotifyError(err error, cfg notifier.Config, skipExitCode int) error {
+	var (
+		skipErr     *notifier.SkipError
+		configErr   *notifier.ConfigError
+		renderErr   *notifier.RenderError
+		deliveryErr *notifier.DeliveryError
+	)
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &skipErr):
+		switch {
+		case errors.Is(err, slack.ErrStatusMismatch):
 			log.Infof("Exiting without posting to Slack: The job status %q does not match the status set to send alerts %q.\n",
-				slackNotification.Status, slackNotification.Event)
-			os.Exit(0)
-		} else if errors.Is(err, slack.ErrPostConditionNotMet) {
+				cfg.Status, cfg.Event)
+		case errors.Is(err, slack.ErrNoMatchingPaths):
+			log.Infof("Exiting without posting to Slack: No changed file matches the path patterns %q.\n", cfg.PathPatterns)
+		case errors.Is(err, slack.ErrSkippedByCommit):
+			log.Infof("Exiting without posting to Slack: The commit message contains [skip slack].\n")
+		case errors.Is(err, notifier.ErrDuplicate):
+			log.Infof("Exiting without posting to Slack: The notification %q was already sent in the last %s.\n",
+				cfg.DedupeKey, cfg.DedupeWindow)
+		case errors.Is(err, notifier.ErrUnknownUser):
+			log.Infof("Exiting without posting to Slack: The ephemeral message is meant for an unknown Slack user and the commit author has no email address.\n")
+		case errors.Is(err, slack.ErrOutsideDeliveryWindow):
+			log.Infof("Exiting without posting to Slack: The current time is outside of the delivery window %q.\n",
+				cfg.DeliveryWindow)
+		default:
 			log.Infof("Exiting without posting to Slack: The post condition is not met. Neither the branch nor the tag matches the pattern or the match is inverted.\n")
-			os.Exit(0)
 		}
-
-		log.Fatalf("Failed to build message body: %v", err)
+		return skipped(skipExitCode)
+	case errors.As(err, &configErr):
+		return withExitCode(ExitConfigError, err)
+	case errors.As(err, &renderErr):
+		return withExitCode(ExitRenderError, err)
+	case errors.As(err, &deliveryErr) && deliveryErr.Abandoned:
+		return withExitCode(ExitDeliveryError,
+			fmt.Errorf("error: \nabandoned sending the message to channel %s and the channels after it: %v", deliveryErr.Channel, deliveryErr.Err))
+	case errors.As(err, &deliveryErr):
+		return withExitCode(ExitDeliveryError, fmt.Errorf("error: \n%v", deliveryErr.Err))
+	default:
+		return err
 	}
+}
 
-	client := slack.NewClient(slack.ClientOptions{
-		SlackToken: secret.String(cfg.AccessToken),
-		BaseURL:    cfg.SlackAPIBaseUrl, // this is okay to set, it's ignored if the value is ""
-	})
+// skipped returns the error that makes the process exit with the
+// configured skip exit code, or nil when it is ExitOK.
+func skipped(skipExitCode int) error {
+	if skipExitCode == ExitOK {
+		return nil
+	}
+	return withExitCode(skipExitCode, nil)
+}
 
-	for _, channel := range channels {
-		log.Debugf("Posting the following JSON to Slack:\n")
-		colorizedJSONWithChannel, err := utils.ColorizeJSON(modifiedJSON)
-		if err != nil {
-			log.Fatalf("Error coloring JSON: %v", err)
+// emitResult prints the result to stdout when the JSON output is selected
+// and writes it to resultFile when one is provided.
+func emitResult(res *result.Result, output, resultFile string) {
+	if output == "json" {
+		if err := res.Write(os.Stdout); err != nil {
+			log.Errorf("Unable to print the result: %v", err)
 		}
-		log.Debug(colorizedJSONWithChannel)
-		err = client.PostMessage(context.Background(), modifiedJSON, channel)
-		if err != nil {
-			if !ignoreErrors {
-				log.Fatalf("Error: \n%v\n", err)
-			}
-
-			log.Errorf("Error: \n%v\n", err)
+	}
+	if resultFile != "" {
+		if err := res.WriteFile(resultFile); err != nil {
+			log.Errorf("Unable to write the result file: %v", err)
 		} else {
-			log.Infof("Successfully posted message to channel: %s", channel)
+			log.Debugf("Result written to %s", resultFile)
 		}
 	}
-
 }
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/result"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/slack"
+)
+
+// reactCmd represents the react command
+var reactCmd = &cobra.Command{
+	Use:   "react",
+	Short: "Add or remove emoji reactions on a posted slack notification",
+	Long: `Add or remove emoji reactions, such as :hourglass: or :white_check_mark:, on messages posted by notify,
+to signal the progress of a job without posting a new message.
+The messages are identified either by --channel and --ts, or by the result file written by "notify --result-file".
+
+Reactions are removed before they are added. Adding a reaction the message already has,
+or removing one it does not have, is not an error.`,
+	Args: cobra.NoArgs,
+	RunE: executeReact,
+}
+
+func init() {
+	rootCmd.AddCommand(reactCmd)
+
+	reactCmd.Flags().StringSlice("add", nil, "The name of an emoji reaction to add, such as white_check_mark. Can be repeated.")
+	viper.BindPFlag("react.add", reactCmd.Flags().Lookup("add"))
+	reactCmd.Flags().StringSlice("remove", nil, "The name of an emoji reaction to remove, such as hourglass. Can be repeated.")
+	viper.BindPFlag("react.remove", reactCmd.Flags().Lookup("remove"))
+	reactCmd.Flags().String("channel", "", "The ID of the channel the message was posted in.")
+	viper.BindPFlag("react.channel", reactCmd.Flags().Lookup("channel"))
+	reactCmd.Flags().String("ts", "", "The timestamp of the message.")
+	viper.BindPFlag("react.ts", reactCmd.Flags().Lookup("ts"))
+	reactCmd.Flags().String("result-file", "", "React to every message posted in the result file written by notify.")
+	viper.BindPFlag("react.result-file", reactCmd.Flags().Lookup("result-file"))
+	viper.BindEnv("react.result-file", "SLACK_STR_RESULT_FILE")
+}
+
+func executeReact(_ *cobra.Command, _ []string) error {
+	add := viper.GetStringSlice("react.add")
+	remove := viper.GetStringSlice("react.remove")
+	if len(add) == 0 && len(remove) == 0 {
+		return withExitCode(ExitConfigError, errors.New("at least one of --add or --remove must be provided"))
+	}
+	targets, err := postedMessages(
+		viper.GetString("react.channel"),
+		viper.GetString("react.ts"),
+		viper.GetString("react.result-file"),
+	)
+	if err != nil {
+		return withExitCode(ExitConfigError, err)
+	}
+	if len(targets) == 0 {
+		log.Infof("There are no posted messages to react to.")
+		return nil
+	}
+
+	cfg, err := loadClientConfig()
+	if err != nil {
+		return err
+	}
+	clients, err := newSlackClients(cfg)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	for _, target := range targets {
+		client, err := clients.forWorkspace(target.Workspace)
+		if err != nil {
+			return withExitCode(ExitConfigError, err)
+		}
+		if err := react(ctx, client, target, add, remove); err != nil {
+			return withExitCode(ExitDeliveryError, err)
+		}
+	}
+
+	return nil
+}
+
+// react removes and adds the reactions on the message of target. Reactions that are
+// already added or removed are skipped.
+func react(ctx context.Context, client *slack.Client, target result.ChannelResult, add, remove []string) error {
+	for _, name := range remove {
+		err := client.RemoveReaction(ctx, target.ChannelID, target.TS, name)
+		switch {
+		case slack.IsAPIError(err, "no_reaction"):
+			log.Infof("The message %s in channel %s has no %s reaction.", target.TS, target.ChannelID, name)
+		case err != nil:
+			return fmt.Errorf("error removing reaction %s from message %s: %v", name, target.TS, err)
+		default:
+			log.Infof("Removed reaction %s from message %s in channel: %s", name, target.TS, target.ChannelID)
+		}
+	}
+	for _, name := range add {
+		err := client.AddReaction(ctx, target.ChannelID, target.TS, name)
+		switch {
+		case slack.IsAPIError(err, "already_reacted"):
+			log.Infof("The message %s in channel %s already has the %s reaction.", target.TS, target.ChannelID, name)
+		case err != nil:
+			return fmt.Errorf("error adding reaction %s to message %s: %v", name, target.TS, err)
+		default:
+			log.Infof("Added reaction %s to message %s in channel: %s", name, target.TS, target.ChannelID)
+		}
+	}
+	return nil
+}
+
+// postedMessages returns the messages identified by the flags, either directly
+// by channel and timestamp, or from a notify result file.
+func postedMessages(channel, ts, resultFile string) ([]result.ChannelResult, error) {
+	if channel != "" || ts != "" {
+		if channel == "" || ts == "" {
+			return nil, errors.New("both --channel and --ts must be provided")
+		}
+		return []result.ChannelResult{{ChannelID: channel, TS: ts}}, nil
+	}
+
+	if resultFile == "" {
+		return nil, errors.New("either --result-file or --channel and --ts must be provided")
+	}
+	res, err := result.ReadFile(resultFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []result.ChannelResult
+	for _, channel := range res.Channels {
+		if channel.TS != "" {
+			targets = append(targets, channel)
+		}
+	}
+	return targets, nil
+}
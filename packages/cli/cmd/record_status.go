@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/ci"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/jobstatus"
+)
+
+// recordStatusCmd represents the record-status command
+var recordStatusCmd = &cobra.Command{
+	Use:   "record-status [pass|fail]",
+	Short: "Record the status of the job for a later notification",
+	Long: `Record the status of the job in the state file read by notify.
+Statuses such as "success" and "failure" are mapped to "pass" and "fail".
+Without an argument, the status is detected from the CI provider.
+
+A recorded failure is kept unless --overwrite is set, so a step running with
+"when: always" can record "pass" after a step recording "fail" with "when: on_fail".`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: executeRecordStatus,
+}
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Print the recorded status of the job",
+	Long:  `Print the status recorded by record-status, either "pass" or "fail".`,
+	Args:  cobra.NoArgs,
+	RunE:  executeStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(recordStatusCmd)
+	rootCmd.AddCommand(statusCmd)
+
+	recordStatusCmd.Flags().String("file", "", "The state file the status is recorded in. Defaults to $SLACK_STR_STATUS_FILE, or /tmp/SLACK_JOB_STATUS and its equivalent in the temporary directory on Windows.")
+	viper.BindPFlag("record-status.file", recordStatusCmd.Flags().Lookup("file"))
+	recordStatusCmd.Flags().Bool("overwrite", false, "Overwrite a recorded failure.")
+	viper.BindPFlag("record-status.overwrite", recordStatusCmd.Flags().Lookup("overwrite"))
+
+	statusCmd.Flags().String("file", "", "The state file the status was recorded in. Defaults to $SLACK_STR_STATUS_FILE, or /tmp/SLACK_JOB_STATUS and its equivalent in the temporary directory on Windows.")
+	viper.BindPFlag("status.file", statusCmd.Flags().Lookup("file"))
+}
+
+func executeRecordStatus(_ *cobra.Command, args []string) error {
+	path := statusFile(viper.GetString("record-status.file"))
+
+	var status string
+	if len(args) > 0 {
+		status = ci.NormalizeStatus(args[0])
+	} else {
+		provider, err := ci.Detect(os.Getenv)
+		if err != nil {
+			return withExitCode(ExitConfigError, err)
+		}
+		build := provider.Build(os.Getenv)
+		if build.Status == "" {
+			return withExitCode(ExitConfigError, fmt.Errorf(
+				"the job status cannot be detected on %s, provide either %q or %q", build.Provider, jobstatus.Pass, jobstatus.Fail))
+		}
+		status = build.Status
+	}
+
+	recorded, err := jobstatus.Record(path, status, viper.GetBool("record-status.overwrite"))
+	if err != nil {
+		return withExitCode(ExitConfigError, err)
+	}
+	if recorded != status {
+		log.Infof("Keeping the job status %q recorded by a previous step in %s", recorded, path)
+		return nil
+	}
+	log.Infof("Recorded the job status %q in %s", recorded, path)
+	return nil
+}
+
+func executeStatus(cmd *cobra.Command, _ []string) error {
+	status, err := jobstatus.Read(statusFile(viper.GetString("status.file")))
+	if errors.Is(err, jobstatus.ErrNotRecorded) {
+		return withExitCode(ExitConfigError, errors.New("no job status was recorded, run record-status first"))
+	}
+	if err != nil {
+		return withExitCode(ExitConfigError, err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), status)
+	return nil
+}
+
+// statusFile returns the state file provided with --file, or the default one.
+func statusFile(path string) string {
+	if path != "" {
+		return path
+	}
+	return jobstatus.Path(os.Getenv)
+}
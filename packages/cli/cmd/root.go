@@ -2,13 +2,18 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	"os"
 
 	"github.com/charmbracelet/log"
+	"github.com/circleci/ex/config/secret"
 	"github.com/muesli/termenv"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/CircleCI-Public/slack-orb-go/packages/cli/config"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/notifier"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/slack"
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -16,11 +21,13 @@ var rootCmd = &cobra.Command{
 	Use:   "slack-orb-cli",
 	Short: "The slack-orb-cli interface for the CircleCI Slack orb",
 	Long:  `The slack-orb-cli by CircleCI is a command-line tool for sending slack notifications as a part of a CI/CD workflow.`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	// Errors are logged by Execute, which also picks the exit code.
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		debugFlag, err := cmd.Flags().GetBool("debug")
 		if err != nil {
-			// Handle the error
-			log.Fatalf("Error accessing debug flag: %v", err)
+			return fmt.Errorf("error accessing debug flag: %v", err)
 		}
 		if debugFlag {
 			_ = os.Setenv("SLACK_BOOL_DEBUG", "true")
@@ -32,50 +39,137 @@ var rootCmd = &cobra.Command{
 			log.SetLevel(log.DebugLevel)
 			log.Debug("Debug logging enabled")
 		}
-		initConfig()
+		return nil
 	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// The process exits with one of the documented exit codes when a command fails.
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		var exitErr *exitError
+		if !errors.As(err, &exitErr) || exitErr.err != nil {
+			log.Error(err)
+		}
+		os.Exit(exitCode(err))
 	}
 }
 
 func init() {
 	rootCmd.PersistentFlags().Bool("debug", false, "Enable debug logging")
+	rootCmd.PersistentFlags().Duration("request-timeout", 0, "Set the time a call to the Slack API or a file upload can take, including its retries, such as \"30s\". Calls default to 10s and uploads to 1m.")
+	viper.BindPFlag("request-timeout", rootCmd.PersistentFlags().Lookup("request-timeout"))
+	viper.BindEnv("request-timeout", "SLACK_STR_REQUEST_TIMEOUT")
+}
+
+// loadConfig loads and validates the Slack configuration from the environment.
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, withExitCode(ExitConfigError, fmt.Errorf("error loading environment configuration: \n%v", err))
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, handleConfigurationError(err)
+	}
+	return cfg, nil
+}
+
+// loadClientConfig loads the configuration for commands that call the Slack API
+// without posting a notification, so only the access token is required.
+func loadClientConfig() (*config.Config, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, withExitCode(ExitConfigError, fmt.Errorf("error loading environment configuration: \n%v", err))
+	}
+	if err := cfg.ValidateAccessToken(); err != nil {
+		return nil, handleConfigurationError(err)
+	}
+	return cfg, nil
+}
+
+// slackClients are the Slack clients of the default workspace and of the workspaces named in SLACK_STR_WORKSPACES.
+type slackClients struct {
+	defaultClient *slack.Client
+	workspaces    map[string]*slack.Client
 }
 
-func initConfig() {
-	err := config.InitConfig()
+func newSlackClients(cfg *config.Config) (*slackClients, error) {
+	workspaces, err := config.LoadWorkspaces(cfg.Workspaces, os.Getenv)
 	if err != nil {
-		log.Fatalf("Error loading environment configuration: \n%v\n", err)
+		return nil, handleConfigurationError(err)
 	}
-	if err := config.SlackConfig.Validate(); err != nil {
-		handleConfigurationError(err)
+	transport, err := slack.NewTransport(slack.TransportOptions{
+		CABundle:   cfg.CABundle,
+		ClientCert: cfg.ClientCert,
+		ClientKey:  cfg.ClientKey,
+	})
+	if err != nil {
+		return nil, withExitCode(ExitConfigError, err)
+	}
+
+	timeout := viper.GetDuration("request-timeout")
+
+	clients := &slackClients{
+		defaultClient: slack.NewClient(slack.ClientOptions{
+			SlackToken: secret.String(cfg.AccessToken),
+			BaseURL:    cfg.SlackAPIBaseUrl, // this is okay to set, it's ignored if the value is ""
+			Transport:  transport,
+			Timeout:    timeout,
+		}),
+		workspaces: map[string]*slack.Client{},
+	}
+	for _, workspace := range workspaces {
+		clients.workspaces[workspace.Name] = slack.NewClient(slack.ClientOptions{
+			SlackToken: secret.String(workspace.AccessToken),
+			BaseURL:    workspace.APIBaseURL,
+			Transport:  transport,
+			Timeout:    timeout,
+		})
+	}
+	return clients, nil
+}
+
+// forWorkspace returns the client of the named workspace, or the default client when the name is empty.
+func (c *slackClients) forWorkspace(name string) (*slack.Client, error) {
+	if name == "" {
+		return c.defaultClient, nil
+	}
+	client, ok := c.workspaces[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown workspace %q, add it to SLACK_STR_WORKSPACES", name)
+	}
+	return client, nil
+}
+
+// senders returns the clients of the named workspaces as notifier senders.
+func (c *slackClients) senders() map[string]notifier.Sender {
+	senders := make(map[string]notifier.Sender, len(c.workspaces))
+	for name, client := range c.workspaces {
+		senders[name] = client
 	}
+	return senders
 }
 
-func handleConfigurationError(err error) {
+func handleConfigurationError(err error) error {
 	var envVarError *config.EnvVarError
 	if errors.As(err, &envVarError) {
 		switch envVarError.VarName {
 		case "SLACK_ACCESS_TOKEN":
-			log.Fatalf(`In order to use the Slack Orb an OAuth token must be present via the SLACK_ACCESS_TOKEN environment variable.
+			//nolint:stylecheck // user message
+			err = errors.New(`In order to use the Slack Orb an OAuth token must be present via the SLACK_ACCESS_TOKEN environment variable.
 Follow the setup guide available in the wiki: https://github.com/CircleCI-Public/slack-orb/wiki/Setup.`,
 			)
 		case "SLACK_STR_CHANNEL":
-			//nolint:lll // user message
-			log.Fatalf(
+			//nolint:lll,stylecheck // user message
+			err = errors.New(
 				`No channel was provided. Please provide one or more channels using the "SLACK_STR_CHANNEL" environment variable or the "channel" parameter.`,
 			)
 		default:
-			log.Fatalf("Configuration validation failed: Environment variable not set: %s", envVarError.VarName)
+			err = fmt.Errorf("configuration validation failed: environment variable not set: %s", envVarError.VarName)
 		}
 	}
 
-	log.Fatal(err)
+	return withExitCode(ExitConfigError, err)
 }
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/charmbracelet/log"
+	"github.com/circleci/ex/config/secret"
+	"github.com/circleci/ex/httpserver"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/interactive"
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Receive the button clicks of slack notifications",
+	Long: `Serve the request URL of the Interactivity settings of a Slack app, at the path /slack/actions.
+Requests are verified with the signing secret of the app, set with SLACK_SIGNING_SECRET.
+
+When a button is clicked, the action configured for its action ID in the --actions file is run:
+a shell command, a webhook, or the approval of a CircleCI hold job.`,
+	Args: cobra.NoArgs,
+	RunE: executeServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().String("addr", ":3000", "The address to listen on.")
+	viper.BindPFlag("serve.addr", serveCmd.Flags().Lookup("addr"))
+	viper.BindEnv("serve.addr", "SLACK_STR_SERVE_ADDR")
+	serveCmd.Flags().String("actions", "", "A JSON file mapping action IDs to the actions run when the buttons are clicked.")
+	viper.BindPFlag("serve.actions", serveCmd.Flags().Lookup("actions"))
+	viper.BindEnv("serve.actions", "SLACK_STR_SERVE_ACTIONS")
+	serveCmd.Flags().String("circleci-api-url", "https://circleci.com", "The base URL of the CircleCI API used to approve hold jobs.")
+	viper.BindPFlag("serve.circleci-api-url", serveCmd.Flags().Lookup("circleci-api-url"))
+	viper.BindEnv("serve.circleci-api-url", "SLACK_STR_CIRCLECI_API_URL")
+	viper.BindEnv("serve.signing-secret", "SLACK_SIGNING_SECRET")
+	viper.BindEnv("serve.circleci-token", "CIRCLE_TOKEN")
+}
+
+func executeServe(_ *cobra.Command, _ []string) error {
+	actionsFile := viper.GetString("serve.actions")
+	if actionsFile == "" {
+		return withExitCode(ExitConfigError, errors.New("the --actions file must be provided"))
+	}
+	actions, err := interactive.LoadActions(actionsFile)
+	if err != nil {
+		return withExitCode(ExitConfigError, err)
+	}
+	server, err := interactive.New(interactive.Options{
+		SigningSecret:   secret.String(viper.GetString("serve.signing-secret")),
+		Actions:         actions,
+		CircleCIBaseURL: viper.GetString("serve.circleci-api-url"),
+		CircleCIToken:   secret.String(viper.GetString("serve.circleci-token")),
+		Logger:          log.Default(),
+	})
+	if err != nil {
+		return withExitCode(ExitConfigError, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv, err := httpserver.New(ctx, httpserver.Config{
+		Name:    "slack-orb-interactive",
+		Addr:    viper.GetString("serve.addr"),
+		Handler: server.Handler(ctx),
+	})
+	if err != nil {
+		return err
+	}
+	log.Infof("Listening for button clicks on http://%s%s", srv.Addr(), interactive.ActionsPath)
+	err = srv.Serve(ctx)
+	server.Wait()
+	return err
+}
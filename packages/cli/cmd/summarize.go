@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/builtin"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/summary"
+)
+
+// summarizeCmd represents the summarize command
+var summarizeCmd = &cobra.Command{
+	Use:   "summarize",
+	Short: "Send a single slack notification summarizing the jobs of a workflow",
+	Long: `Send one notification listing the jobs recorded by "notify --record-only",
+with their status, duration and link. Run it in the last job of the workflow.
+
+The notification uses the workflow_summary_1 template unless another template is
+configured, and its status is "fail" when any job failed. It accepts the flags of notify.`,
+	PreRunE: bindNotifyFlags,
+	RunE:    executeSummarize,
+}
+
+func init() {
+	rootCmd.AddCommand(summarizeCmd)
+
+	addNotifyFlags(summarizeCmd)
+}
+
+func executeSummarize(cmd *cobra.Command, args []string) error {
+	if viper.GetBool("record-only") {
+		return withExitCode(ExitConfigError, errors.New("--record-only cannot be used with summarize"))
+	}
+
+	dir := viper.GetString("record-dir")
+	records, err := summary.ReadRecords(dir)
+	if err != nil {
+		return withExitCode(ExitConfigError, err)
+	}
+	if len(records) == 0 {
+		return withExitCode(ExitConfigError, fmt.Errorf("no job records were found in %s", dir))
+	}
+	s := summary.Summary{Records: records}
+	log.Infof("Summarizing %d jobs recorded in %s", len(s.Jobs()), dir)
+
+	if err := builtin.Set(builtin.Workflow{Summary: s}); err != nil {
+		return withExitCode(ExitConfigError, err)
+	}
+	// The notification is about the workflow rather than the job running it. The defaults are
+	// overridden by SLACK_STR_EVENT and by the template settings.
+	viper.Set("JobStatus", s.Status())
+	viper.SetDefault("EventToSendMessage", "always")
+	viper.SetDefault("TemplateName", "workflow_summary_1")
+
+	return executeNotify(cmd, args)
+}
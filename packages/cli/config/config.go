@@ -8,7 +8,6 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/a8m/envsubst"
 	"github.com/charmbracelet/log"
@@ -16,18 +15,19 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
 
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/builtin"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/ci"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/jobstatus"
 	"github.com/CircleCI-Public/slack-orb-go/packages/cli/utils"
 )
 
-var SlackConfig Config
-
 // Config represents the configuration loaded from environment variables.
 type Config struct {
 	// Required configuration
 	AccessToken string
 	Channels    string
 
-	// Trigger matching
+	// Trigger matching. The job branch, status and tag are read from the environment of the CI provider.
 	BranchPattern      string
 	TagPattern         string
 	EventToSendMessage string
@@ -46,62 +46,82 @@ type Config struct {
 	TemplatePath   string
 	TemplateVar    string
 
+	// Workspaces are the names of the workspaces, other than the one of AccessToken, that channels
+	// can be sent to as "<workspace>:<channel>". Their profiles are loaded with LoadWorkspaces.
+	Workspaces string
+
+	// TLS settings of the connections to Slack, such as from behind a proxy intercepting TLS.
+	// The proxy itself is read from HTTPS_PROXY and NO_PROXY.
+	CABundle   string
+	ClientCert string
+	ClientKey  string
+
 	// Overridable for testing
 	SlackAPIBaseUrl string
 }
 
-// InitConfig will initialize the configuration from environment variables.
-// This will set 'SlackConfig' to the loaded configuration.
-func InitConfig() error {
+// Load loads the configuration from environment variables.
+func Load() (*Config, error) {
 	if err := bindEnv(); err != nil {
-		return err
+		return nil, err
 	}
 
 	var cfg Config
 	if err := viper.Unmarshal(&cfg); err != nil {
-		return errors.New("unable to bind configuration")
+		return nil, errors.New("unable to bind configuration")
 	}
 
-	SlackConfig = cfg
-	return nil
-}
-
-// Add built-in environment variables to BASH_ENV
-func initBuiltInEnvVars() {
-	// create new temp file to store environment variables
-	f, err := os.CreateTemp("", "slack-orb-built-in-env")
+	build, err := ci.Current(os.Getenv)
 	if err != nil {
-		log.Fatalf("unable to create temp file for built-in env vars: %s", err)
-		return
+		return nil, err
 	}
-	defer f.Close()
-	timeFormat := viper.GetString("time-format")
-	currentTime := time.Now().Format(timeFormat)
-	_, err = f.WriteString(fmt.Sprintf("SLACK_ORB_TIME_NOW=\"%s\"\n", currentTime))
-
-	if err != nil {
-		log.Fatalf("unable to write to temp file for built-in env vars: %s", err)
+	log.Debugf("Detected the %s CI provider", build.Provider)
+	cfg.JobBranch = build.Branch
+	// Commands notifying about more than the current job, such as summarize, set the status in viper
+	if cfg.JobStatus == "" {
+		cfg.JobStatus = build.Status
 	}
+	cfg.JobTag = build.Tag
 
-	// load environment variables from temp file
-	if err := godotenv.Load(f.Name()); err != nil {
-		log.Fatalf("unable to load built-in env vars: %s", err)
-	}
+	return &cfg, nil
+}
 
+// initBuiltInEnvVars sets the built-in SLACK_ORB_* variables available to templates.
+func initBuiltInEnvVars() error {
+	return builtin.Set(
+		builtin.Time{Format: viper.GetString("time-format")},
+		builtin.CI{},
+		builtin.Git{
+			From:       viper.GetString("changelog-from"),
+			To:         viper.GetString("changelog-to"),
+			MaxCommits: viper.GetInt("changelog-limit"),
+		},
+		builtin.TestResults{
+			Path:        viper.GetString("test-results"),
+			MaxFailures: viper.GetInt("test-failures-limit"),
+		},
+		builtin.Coverage{
+			Path:      viper.GetString("coverage"),
+			Baseline:  viper.GetString("coverage-baseline"),
+			Threshold: viper.GetFloat64("coverage-threshold"),
+		},
+	)
 }
 
 func bindEnv() error {
-	// Load environment variables from BASH_ENV and SLACK_JOB_STATUS files
+	// Load environment variables from the BASH_ENV file and the job status recorded by record-status
 	// This has to be done before loading the configuration because the configuration
 	// depends on the environment variables loaded from these files
 	if err := loadEnvFromFile(os.Getenv("BASH_ENV")); err != nil {
 		return err
 	}
-	if err := loadEnvFromFile("/tmp/SLACK_JOB_STATUS"); err != nil {
+	if err := jobstatus.Load(jobstatus.Path(os.Getenv)); err != nil {
 		return err
 	}
 
-	initBuiltInEnvVars()
+	if err := initBuiltInEnvVars(); err != nil {
+		return err
+	}
 
 	var errs error
 	for k, v := range map[string]string{
@@ -111,9 +131,6 @@ func bindEnv() error {
 		"EventToSendMessage": "SLACK_STR_EVENT",
 		"IgnoreErrors":       "SLACK_BOOL_IGNORE_ERRORS",
 		"InvertMatch":        "SLACK_BOOL_INVERT_MATCH",
-		"JobBranch":          "CIRCLE_BRANCH",
-		"JobStatus":          "CCI_STATUS",
-		"JobTag":             "CIRCLE_TAG",
 		"SlackAPIBaseUrl":    "TEST_SLACK_API_BASE_URL",
 		"TagPattern":         "SLACK_STR_TAGPATTERN",
 		"TemplateInline":     "SLACK_STR_TEMPLATE_INLINE",
@@ -121,6 +138,10 @@ func bindEnv() error {
 		"TemplatePath":       "SLACK_STR_TEMPLATE_PATH",
 		"TemplateVar":        "SLACK_STR_TEMPLATE_VAR",
 		"Debug":              "SLACK_BOOL_DEBUG",
+		"Workspaces":         "SLACK_STR_WORKSPACES",
+		"CABundle":           "SLACK_STR_CA_BUNDLE",
+		"ClientCert":         "SLACK_STR_CLIENT_CERT",
+		"ClientKey":          "SLACK_STR_CLIENT_KEY",
 	} {
 		errs = multierror.Append(errs, viper.BindEnv(k, v))
 	}
@@ -158,6 +179,10 @@ func (c *Config) expandEnvVariables() error {
 		"TemplateName":       &c.TemplateName,
 		"TemplatePath":       &c.TemplatePath,
 		"TemplateVar":        &c.TemplateVar,
+		"Workspaces":         &c.Workspaces,
+		"CABundle":           &c.CABundle,
+		"ClientCert":         &c.ClientCert,
+		"ClientKey":          &c.ClientKey,
 	}
 
 	for fieldName, fieldValue := range fields {
@@ -174,11 +199,8 @@ func (c *Config) expandEnvVariables() error {
 
 // Validate checks whether the necessary environment variables are set.
 func (c *Config) Validate() error {
-	if err := c.expandEnvVariables(); err != nil {
-		return fmt.Errorf("error expanding environment variables: %v", err)
-	}
-	if c.AccessToken == "" {
-		return &EnvVarError{VarName: "SLACK_ACCESS_TOKEN"}
+	if err := c.ValidateAccessToken(); err != nil {
+		return err
 	}
 	if c.Channels == "" {
 		return &EnvVarError{VarName: "SLACK_STR_CHANNEL"}
@@ -189,6 +211,18 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// ValidateAccessToken checks whether the access token is set. It is enough
+// for commands that call the Slack API without posting a notification.
+func (c *Config) ValidateAccessToken() error {
+	if err := c.expandEnvVariables(); err != nil {
+		return fmt.Errorf("error expanding environment variables: %v", err)
+	}
+	if c.AccessToken == "" {
+		return &EnvVarError{VarName: "SLACK_ACCESS_TOKEN"}
+	}
+	return nil
+}
+
 // handleOSSpecifics checks and applies OS-specific modifications to the file.
 func handleOSSpecifics(filePath string) (string, error) {
 	if runtime.GOOS == "windows" {
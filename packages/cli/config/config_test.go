@@ -5,6 +5,8 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/spf13/viper"
 )
 
 func TestExpandEnvVariables(t *testing.T) {
@@ -149,6 +151,63 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestInitBuiltInEnvVars(t *testing.T) {
+	viper.Set("test-results", "../junit/testdata/results.xml")
+	viper.Set("test-failures-limit", 1)
+	viper.Set("time-format", "15:04")
+	t.Cleanup(viper.Reset)
+	for _, name := range []string{"SLACK_ORB_TIME_NOW", "SLACK_ORB_TESTS_TOTAL", "SLACK_ORB_TEST_FAILURES"} {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+	// Variables set by the user take precedence
+	t.Setenv("SLACK_ORB_TESTS_FAILED", "42")
+
+	if err := initBuiltInEnvVars(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"SLACK_ORB_TESTS_TOTAL":   "4",
+		"SLACK_ORB_TESTS_FAILED":  "42",
+		"SLACK_ORB_TEST_FAILURES": "• `api.UsersTest.test_delete`: expected 204, got 500\n…and 1 more",
+	}
+	for name, value := range expected {
+		if got := os.Getenv(name); got != value {
+			t.Errorf("Expected %s to be %q, got %q", name, value, got)
+		}
+	}
+	if os.Getenv("SLACK_ORB_TIME_NOW") == "" {
+		t.Errorf("Expected SLACK_ORB_TIME_NOW to be set")
+	}
+}
+
+func TestInitBuiltInEnvVarsCoverage(t *testing.T) {
+	viper.Set("coverage", "../coverage/testdata/lcov.info")
+	viper.Set("coverage-baseline", "../coverage/testdata/cobertura.xml")
+	viper.Set("coverage-threshold", 5.0)
+	t.Cleanup(viper.Reset)
+	for _, name := range []string{"SLACK_ORB_COVERAGE", "SLACK_ORB_COVERAGE_DELTA", "SLACK_ORB_COVERAGE_SUMMARY"} {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+
+	if err := initBuiltInEnvVars(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"SLACK_ORB_COVERAGE":         "65.0%",
+		"SLACK_ORB_COVERAGE_DELTA":   "-10.0%",
+		"SLACK_ORB_COVERAGE_SUMMARY": ":warning: *Coverage dropped by 10.0% to 65.0%*",
+	}
+	for name, value := range expected {
+		if got := os.Getenv(name); got != value {
+			t.Errorf("Expected %s to be %q, got %q", name, value, got)
+		}
+	}
+}
+
 func TestLoadEnvFromFile(t *testing.T) {
 	tests := []struct {
 		description string
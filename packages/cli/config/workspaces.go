@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/slack"
+)
+
+var workspaceName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// Workspace is the profile of a named Slack workspace, which channels such as "status:incidents" are sent to.
+type Workspace struct {
+	Name        string
+	AccessToken string
+	// APIBaseURL is the URL of the Slack API of the workspace. The default URL is used when it is empty.
+	APIBaseURL string
+}
+
+// LoadWorkspaces returns the profiles of the workspaces listed in names, such as "eng,status".
+// The access token of a workspace is read from SLACK_ACCESS_TOKEN_<NAME> and the URL of its API
+// from SLACK_API_BASE_URL_<NAME>, where <NAME> is the upper-cased name with dashes replaced by underscores.
+func LoadWorkspaces(names string, getenv func(string) string) ([]Workspace, error) {
+	var workspaces []Workspace
+	seen := map[string]bool{}
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if !workspaceName.MatchString(name) || slack.IsRecipientType(name) {
+			return nil, fmt.Errorf("invalid workspace name %q", name)
+		}
+
+		suffix := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		workspace := Workspace{
+			Name:        name,
+			AccessToken: getenv("SLACK_ACCESS_TOKEN_" + suffix),
+			APIBaseURL:  getenv("SLACK_API_BASE_URL_" + suffix),
+		}
+		if workspace.AccessToken == "" {
+			return nil, &EnvVarError{VarName: "SLACK_ACCESS_TOKEN_" + suffix}
+		}
+		workspaces = append(workspaces, workspace)
+	}
+	return workspaces, nil
+}
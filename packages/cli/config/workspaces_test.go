@@ -0,0 +1,47 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestLoadWorkspaces(t *testing.T) {
+	env := map[string]string{
+		"SLACK_ACCESS_TOKEN_ENG":           "eng-token",
+		"SLACK_ACCESS_TOKEN_STATUS_PAGE":   "status-token",
+		"SLACK_API_BASE_URL_STATUS_PAGE":   "https://slack.example.com/api",
+		"SLACK_ACCESS_TOKEN_USER":          "user-token",
+		"SLACK_API_BASE_URL_WITHOUT_TOKEN": "https://slack.example.com/api",
+	}
+	getenv := func(key string) string { return env[key] }
+
+	workspaces, err := LoadWorkspaces("eng, status-page,eng", getenv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Workspace{
+		{Name: "eng", AccessToken: "eng-token"},
+		{Name: "status-page", AccessToken: "status-token", APIBaseURL: "https://slack.example.com/api"},
+	}
+	if !reflect.DeepEqual(workspaces, expected) {
+		t.Errorf("expected %v, got %v", expected, workspaces)
+	}
+
+	workspaces, err = LoadWorkspaces("", getenv)
+	if err != nil || len(workspaces) != 0 {
+		t.Errorf("expected no workspaces, got %v, %v", workspaces, err)
+	}
+
+	var envVarErr *EnvVarError
+	_, err = LoadWorkspaces("without-token", getenv)
+	if !errors.As(err, &envVarErr) || envVarErr.VarName != "SLACK_ACCESS_TOKEN_WITHOUT_TOKEN" {
+		t.Errorf("expected a missing SLACK_ACCESS_TOKEN_WITHOUT_TOKEN error, got %v", err)
+	}
+
+	for _, name := range []string{"user", "group", "status:page", "-eng"} {
+		if _, err := LoadWorkspaces(name, getenv); err == nil {
+			t.Errorf("expected the workspace name %q to be invalid", name)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Package coverage reads code coverage reports for use in notification templates.
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Load returns the line or statement coverage, in percent, of the report at path.
+// Go cover profiles, Cobertura XML and LCOV reports are detected from their content.
+func Load(path string) (float64, error) {
+	//nolint:gosec // G304 the path is provided by the user on purpose
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read coverage report: %w", err)
+	}
+
+	var percent float64
+	trimmed := bytes.TrimSpace(content)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		percent, err = parseGoCover(trimmed)
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		percent, err = parseCobertura(trimmed)
+	case bytes.HasPrefix(trimmed, []byte("TN:")), bytes.HasPrefix(trimmed, []byte("SF:")):
+		percent, err = parseLCOV(trimmed)
+	default:
+		err = errors.New("unknown format, must be a Go cover profile, Cobertura XML or LCOV report")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse coverage report %q: %w", path, err)
+	}
+	return percent, nil
+}
+
+func ratio(covered, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(covered) / float64(total) * 100
+}
+
+// parseGoCover reads a profile written by "go test -coverprofile".
+// Each line is "file:startLine.startCol,endLine.endCol numStatements count".
+func parseGoCover(content []byte) (float64, error) {
+	type block struct {
+		statements int64
+		covered    bool
+	}
+	// Blocks are repeated for every test binary when -coverpkg is used, so merge them by position
+	blocks := map[string]*block{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Scan() // skip the mode line
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return 0, fmt.Errorf("invalid line %q", line)
+		}
+		statements, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid line %q", line)
+		}
+		count, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid line %q", line)
+		}
+
+		b, ok := blocks[fields[0]]
+		if !ok {
+			b = &block{statements: statements}
+			blocks[fields[0]] = b
+		}
+		b.covered = b.covered || count > 0
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	var covered, total int64
+	for _, b := range blocks {
+		total += b.statements
+		if b.covered {
+			covered += b.statements
+		}
+	}
+	return ratio(covered, total), nil
+}
+
+type coberturaReport struct {
+	XMLName      xml.Name `xml:"coverage"`
+	LineRate     string   `xml:"line-rate,attr"`
+	LinesCovered string   `xml:"lines-covered,attr"`
+	LinesValid   string   `xml:"lines-valid,attr"`
+}
+
+// parseCobertura reads the totals from the root element of a Cobertura XML report.
+func parseCobertura(content []byte) (float64, error) {
+	var report coberturaReport
+	if err := xml.Unmarshal(content, &report); err != nil {
+		return 0, err
+	}
+
+	// Prefer the line counts, which are more precise than the rounded rate
+	covered, errCovered := strconv.ParseInt(report.LinesCovered, 10, 64)
+	valid, errValid := strconv.ParseInt(report.LinesValid, 10, 64)
+	if errCovered == nil && errValid == nil {
+		return ratio(covered, valid), nil
+	}
+
+	rate, err := strconv.ParseFloat(report.LineRate, 64)
+	if err != nil {
+		return 0, errors.New("the report has no line-rate")
+	}
+	return rate * 100, nil
+}
+
+// parseLCOV sums the lines found (LF) and hit (LH) of every source file in an LCOV report.
+func parseLCOV(content []byte) (float64, error) {
+	var found, hit int64
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if key != "LF" && key != "LH" {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s record %q", key, value)
+		}
+		if key == "LF" {
+			found += n
+		} else {
+			hit += n
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return ratio(hit, found), nil
+}
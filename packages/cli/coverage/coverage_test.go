@@ -0,0 +1,95 @@
+package coverage
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected float64
+		wantErr  string
+	}{
+		{name: "go cover profile with repeated blocks", path: "testdata/cover.out", expected: 87.5},
+		{name: "cobertura", path: "testdata/cobertura.xml", expected: 75},
+		{name: "cobertura with the line rate only", path: "testdata/cobertura_rate_only.xml", expected: 62.5},
+		{name: "lcov", path: "testdata/lcov.info", expected: 65},
+		{name: "unknown format", path: "testdata/unknown.txt", wantErr: "unknown format"},
+		{name: "missing report", path: "testdata/missing.out", wantErr: "unable to read coverage report"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percent, err := Load(tt.path)
+			if tt.wantErr != "" {
+				assert.Check(t, cmp.ErrorContains(err, tt.wantErr))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, cmp.Equal(percent, tt.expected))
+		})
+	}
+}
+
+func TestSummaryEnvVars(t *testing.T) {
+	tests := []struct {
+		name     string
+		summary  Summary
+		expected map[string]string
+	}{
+		{
+			name:    "without a baseline",
+			summary: Summary{Coverage: 81.25, Threshold: 1},
+			expected: map[string]string{
+				"SLACK_ORB_COVERAGE":         "81.2%",
+				"SLACK_ORB_COVERAGE_SUMMARY": "Coverage is 81.2%",
+			},
+		},
+		{
+			name:    "improvement",
+			summary: Summary{Coverage: 82.5, Baseline: 80, HasBaseline: true, Threshold: 1},
+			expected: map[string]string{
+				"SLACK_ORB_COVERAGE":         "82.5%",
+				"SLACK_ORB_COVERAGE_DELTA":   "+2.5%",
+				"SLACK_ORB_COVERAGE_SUMMARY": "Coverage is 82.5% (+2.5%)",
+			},
+		},
+		{
+			name:    "drop within the threshold",
+			summary: Summary{Coverage: 79.5, Baseline: 80, HasBaseline: true, Threshold: 1},
+			expected: map[string]string{
+				"SLACK_ORB_COVERAGE":         "79.5%",
+				"SLACK_ORB_COVERAGE_DELTA":   "-0.5%",
+				"SLACK_ORB_COVERAGE_SUMMARY": "Coverage is 79.5% (-0.5%)",
+			},
+		},
+		{
+			name:    "drop smaller than the precision",
+			summary: Summary{Coverage: 79.96, Baseline: 80, HasBaseline: true, Threshold: 1},
+			expected: map[string]string{
+				"SLACK_ORB_COVERAGE":         "80.0%",
+				"SLACK_ORB_COVERAGE_DELTA":   "0.0%",
+				"SLACK_ORB_COVERAGE_SUMMARY": "Coverage is 80.0% (0.0%)",
+			},
+		},
+		{
+			name:    "regression past the threshold",
+			summary: Summary{Coverage: 77.9, Baseline: 80, HasBaseline: true, Threshold: 1},
+			expected: map[string]string{
+				"SLACK_ORB_COVERAGE":         "77.9%",
+				"SLACK_ORB_COVERAGE_DELTA":   "-2.1%",
+				"SLACK_ORB_COVERAGE_SUMMARY": ":warning: *Coverage dropped by 2.1% to 77.9%*",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Check(t, cmp.DeepEqual(tt.summary.EnvVars(), tt.expected))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package coverage
+
+import (
+	"fmt"
+	"math"
+)
+
+// Summary compares the coverage of a build with the coverage of a baseline,
+// such as the last build of the default branch.
+type Summary struct {
+	Coverage float64
+	// Baseline is the coverage to compare with. It is ignored when HasBaseline is false.
+	Baseline    float64
+	HasBaseline bool
+	// Threshold is the drop, in percentage points, from which the coverage is a regression.
+	Threshold float64
+}
+
+// Delta returns the change in percentage points since the baseline, rounded to a tenth.
+func (s *Summary) Delta() float64 {
+	delta := math.Round((s.Coverage-s.Baseline)*10) / 10
+	if delta == 0 {
+		// Drops smaller than the precision round to a negative zero
+		return 0
+	}
+	return delta
+}
+
+// formatDelta formats the delta with its sign, except when it is zero.
+func (s *Summary) formatDelta() string {
+	if s.Delta() == 0 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%+.1f%%", s.Delta())
+}
+
+// IsRegression reports whether the coverage dropped by more than the threshold.
+func (s *Summary) IsRegression() bool {
+	return s.HasBaseline && -s.Delta() > s.Threshold
+}
+
+// EnvVars returns the built-in variables describing the summary. SLACK_ORB_COVERAGE_DELTA
+// is only set when there is a baseline.
+func (s *Summary) EnvVars() map[string]string {
+	vars := map[string]string{
+		"SLACK_ORB_COVERAGE":         fmt.Sprintf("%.1f%%", s.Coverage),
+		"SLACK_ORB_COVERAGE_SUMMARY": s.summary(),
+	}
+	if s.HasBaseline {
+		vars["SLACK_ORB_COVERAGE_DELTA"] = s.formatDelta()
+	}
+	return vars
+}
+
+// summary is a mrkdwn line that highlights regressions.
+func (s *Summary) summary() string {
+	switch {
+	case !s.HasBaseline:
+		return fmt.Sprintf("Coverage is %.1f%%", s.Coverage)
+	case s.IsRegression():
+		return fmt.Sprintf(":warning: *Coverage dropped by %.1f%% to %.1f%%*", -s.Delta(), s.Coverage)
+	default:
+		return fmt.Sprintf("Coverage is %.1f%% (%s)", s.Coverage, s.formatDelta())
+	}
+}
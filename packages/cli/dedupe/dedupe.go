@@ -0,0 +1,111 @@
+// Package dedupe remembers the notifications that were sent, so that repeated notifications,
+// such as the failures of a flaky job retried several times, can be suppressed or threaded.
+package dedupe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Policies applied to duplicate notifications.
+const (
+	// PolicySuppress does not send duplicates.
+	PolicySuppress = "suppress"
+	// PolicyThread replies to the first notification with duplicates and updates its counter.
+	PolicyThread = "thread"
+)
+
+// Key returns the dedupe key made of parts, such as the project, branch, job and status.
+func Key(parts ...string) string {
+	return strings.Join(parts, "/")
+}
+
+// Message is the first notification posted to a channel for a key.
+type Message struct {
+	Channel   string `json:"channel"`
+	ChannelID string `json:"channel_id"`
+	TS        string `json:"ts"`
+	// Workspace is the name of the workspace the channel is in, or empty for the default workspace.
+	Workspace string `json:"workspace,omitempty"`
+}
+
+// Entry is what is remembered about the notifications sent for a key.
+type Entry struct {
+	// FirstSeen is when the first notification was sent. The window starts at that time.
+	FirstSeen time.Time `json:"first_seen"`
+	// Count is the number of notifications for the key since FirstSeen, including the first one.
+	Count    int       `json:"count"`
+	Messages []Message `json:"messages,omitempty"`
+}
+
+// Within reports whether now is less than window after the first notification.
+func (e *Entry) Within(window time.Duration, now time.Time) bool {
+	return now.Sub(e.FirstSeen) < window
+}
+
+// Store keeps the entries between runs. Implementations can keep them anywhere,
+// such as in a file saved to the CircleCI cache or in a shared database.
+type Store interface {
+	// Get returns the entry for key, or nil when there is none.
+	Get(key string) (*Entry, error)
+	Put(key string, entry Entry) error
+}
+
+// FileStore keeps the entries in a JSON file, which can be persisted across jobs
+// with a workspace or a cache. It is not safe for concurrent use by several processes.
+type FileStore struct {
+	Path string
+}
+
+func (s FileStore) Get(key string) (*Entry, error) {
+	entries, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := entries[key]
+	if !ok {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+func (s FileStore) Put(key string, entry Entry) error {
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+	entries[key] = entry
+
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return fmt.Errorf("unable to create the dedupe state directory: %w", err)
+	}
+	//nolint:gosec // the state file is meant to be saved to a workspace or cache
+	if err := os.WriteFile(s.Path, content, 0o644); err != nil {
+		return fmt.Errorf("unable to write the dedupe state: %w", err)
+	}
+	return nil
+}
+
+func (s FileStore) read() (map[string]Entry, error) {
+	entries := map[string]Entry{}
+	content, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the dedupe state: %w", err)
+	}
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, fmt.Errorf("unable to parse the dedupe state %q: %w", s.Path, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,56 @@
+package dedupe
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func TestFileStore(t *testing.T) {
+	store := FileStore{Path: filepath.Join(t.TempDir(), "state", "dedupe.json")}
+	firstSeen := time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC)
+
+	entry, err := store.Get("repo/main/test/fail")
+	assert.NilError(t, err)
+	assert.Check(t, entry == nil)
+
+	expected := Entry{
+		FirstSeen: firstSeen,
+		Count:     2,
+		Messages:  []Message{{Channel: "alerts", ChannelID: "C123", TS: "1700000000.000100"}},
+	}
+	assert.NilError(t, store.Put("repo/main/test/fail", expected))
+	assert.NilError(t, store.Put("repo/main/test/pass", Entry{FirstSeen: firstSeen, Count: 1}))
+
+	entry, err = store.Get("repo/main/test/fail")
+	assert.NilError(t, err)
+	assert.Check(t, cmp.DeepEqual(*entry, expected))
+
+	entry, err = store.Get("repo/main/test/pass")
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Equal(entry.Count, 1))
+}
+
+func TestFileStoreInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dedupe.json")
+	assert.NilError(t, os.WriteFile(path, []byte("not json"), 0o600))
+
+	_, err := FileStore{Path: path}.Get("key")
+	assert.Check(t, cmp.ErrorContains(err, "unable to parse the dedupe state"))
+}
+
+func TestEntryWithin(t *testing.T) {
+	firstSeen := time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC)
+	entry := Entry{FirstSeen: firstSeen, Count: 1}
+
+	assert.Check(t, entry.Within(time.Hour, firstSeen.Add(59*time.Minute)))
+	assert.Check(t, !entry.Within(time.Hour, firstSeen.Add(time.Hour)))
+}
+
+func TestKey(t *testing.T) {
+	assert.Check(t, cmp.Equal(Key("repo", "main", "test", "fail"), "repo/main/test/fail"))
+}
@@ -0,0 +1,51 @@
+// Package git runs git commands against a local checkout.
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Run runs git with args in dir, or in the working directory when dir is empty,
+// and returns its trimmed output.
+func Run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// IsRepository reports whether dir is inside a git work tree.
+func IsRepository(dir string) bool {
+	_, err := Run(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil
+}
+
+// HasRevision reports whether rev, such as "HEAD~1" or "origin/main", names a commit of the repository in dir.
+// It does not in a shallow clone that does not include it, or when HEAD~1 is asked after the first commit.
+func HasRevision(dir, rev string) bool {
+	_, err := Run(dir, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	return err == nil
+}
+
+// ChangedFiles returns the paths, relative to the root of the repository, of the files
+// changed between the merge base of base and HEAD, and HEAD.
+func ChangedFiles(dir, base string) ([]string, error) {
+	out, err := Run(dir, "diff", "--name-only", base+"...HEAD")
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
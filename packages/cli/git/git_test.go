@@ -0,0 +1,58 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func TestChangedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		_, err := Run(dir, append([]string{"-c", "user.name=Jane Doe", "-c", "user.email=jane@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		assert.NilError(t, err)
+	}
+	write := func(path string) {
+		t.Helper()
+		assert.NilError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), 0o700))
+		assert.NilError(t, os.WriteFile(filepath.Join(dir, path), []byte(path), 0o600))
+	}
+
+	run("init", "--quiet")
+	write("README.md")
+	run("add", ".")
+	run("commit", "--quiet", "-m", "Initial commit")
+	run("tag", "base")
+
+	write("services/api/main.go")
+	write("services/web/index.html")
+	run("add", ".")
+	run("commit", "--quiet", "-m", "Add the services")
+
+	assert.Check(t, IsRepository(dir))
+	assert.Check(t, !IsRepository(t.TempDir()))
+	assert.Check(t, HasRevision(dir, "base"))
+	assert.Check(t, HasRevision(dir, "HEAD~1"))
+	assert.Check(t, !HasRevision(dir, "HEAD~2"))
+	assert.Check(t, !HasRevision(dir, "does-not-exist"))
+
+	files, err := ChangedFiles(dir, "base")
+	assert.NilError(t, err)
+	assert.Check(t, cmp.DeepEqual(files, []string{"services/api/main.go", "services/web/index.html"}))
+
+	files, err = ChangedFiles(dir, "HEAD")
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Len(files, 0))
+
+	_, err = ChangedFiles(dir, "does-not-exist")
+	assert.Check(t, cmp.ErrorContains(err, "git diff"))
+}
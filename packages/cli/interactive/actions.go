@@ -0,0 +1,155 @@
+package interactive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/circleci/ex/httpclient"
+)
+
+// Types of the actions run when a button is clicked.
+const (
+	// TypeCommand runs a shell command with the details of the click in its environment.
+	TypeCommand = "command"
+	// TypeWebhook posts the details of the click as JSON to a URL.
+	TypeWebhook = "webhook"
+	// TypeCircleCIApproval approves a CircleCI hold job. The value of the button must be
+	// "<workflow ID>/<approval request ID>".
+	TypeCircleCIApproval = "circleci-approval"
+)
+
+// Action is what is run when the button with an action ID is clicked.
+type Action struct {
+	Type    string `json:"type"`
+	Command string `json:"command,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+func (a Action) validate() error {
+	switch a.Type {
+	case TypeCommand:
+		if a.Command == "" {
+			return errors.New("a command action must have a command")
+		}
+	case TypeWebhook:
+		if a.URL == "" {
+			return errors.New("a webhook action must have a url")
+		}
+	case TypeCircleCIApproval:
+	default:
+		return fmt.Errorf("unknown action type %q", a.Type)
+	}
+	return nil
+}
+
+// LoadActions reads the actions from a JSON file mapping action IDs to actions.
+func LoadActions(path string) (map[string]Action, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the actions file: %w", err)
+	}
+	var actions map[string]Action
+	if err := json.Unmarshal(content, &actions); err != nil {
+		return nil, fmt.Errorf("unable to parse the actions file %q: %w", path, err)
+	}
+	for id, action := range actions {
+		if err := action.validate(); err != nil {
+			return nil, fmt.Errorf("action %q: %w", id, err)
+		}
+	}
+	return actions, nil
+}
+
+// Event describes a click. It is posted to webhooks and set in the environment of commands.
+type Event struct {
+	ActionID    string `json:"action_id"`
+	Value       string `json:"value"`
+	UserID      string `json:"user_id"`
+	Username    string `json:"username"`
+	ChannelID   string `json:"channel_id"`
+	MessageTS   string `json:"message_ts"`
+	ResponseURL string `json:"response_url"`
+}
+
+func newEvent(payload *Payload, action BlockAction) Event {
+	return Event{
+		ActionID:    action.ActionID,
+		Value:       action.Value,
+		UserID:      payload.User.ID,
+		Username:    payload.User.Username,
+		ChannelID:   payload.Channel.ID,
+		MessageTS:   payload.Message.TS,
+		ResponseURL: payload.ResponseURL,
+	}
+}
+
+func (e Event) environ() []string {
+	return append(os.Environ(),
+		"SLACK_ACTION_ID="+e.ActionID,
+		"SLACK_ACTION_VALUE="+e.Value,
+		"SLACK_USER_ID="+e.UserID,
+		"SLACK_USERNAME="+e.Username,
+		"SLACK_CHANNEL_ID="+e.ChannelID,
+		"SLACK_MESSAGE_TS="+e.MessageTS,
+		"SLACK_RESPONSE_URL="+e.ResponseURL,
+	)
+}
+
+// run runs the action for the click.
+func (s *Server) run(ctx context.Context, action Action, event Event) error {
+	switch action.Type {
+	case TypeCommand:
+		return s.runCommand(ctx, action.Command, event)
+	case TypeWebhook:
+		return s.callWebhook(ctx, action.URL, event)
+	case TypeCircleCIApproval:
+		return s.approve(ctx, event.Value)
+	}
+	return fmt.Errorf("unknown action type %q", action.Type)
+}
+
+func (s *Server) runCommand(ctx context.Context, command string, event Event) error {
+	//nolint:gosec // running the configured command is the point
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = event.environ()
+	output, err := cmd.CombinedOutput()
+	if len(output) > 0 {
+		s.logger.Infof("Output of the command for %s:\n%s", event.ActionID, output)
+	}
+	if err != nil {
+		return fmt.Errorf("the command failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) callWebhook(ctx context.Context, webhookURL string, event Event) error {
+	req := httpclient.NewRequest("POST", "%s",
+		httpclient.RouteParams(webhookURL),
+		httpclient.Body(event),
+	)
+	if err := s.webhooks.Call(ctx, req); err != nil {
+		return fmt.Errorf("the webhook failed: %w", err)
+	}
+	return nil
+}
+
+// approve approves the approval request identified by value, "<workflow ID>/<approval request ID>".
+func (s *Server) approve(ctx context.Context, value string) error {
+	workflowID, approvalRequestID, ok := strings.Cut(value, "/")
+	if !ok || workflowID == "" || approvalRequestID == "" {
+		return fmt.Errorf("the button value %q is not <workflow ID>/<approval request ID>", value)
+	}
+	req := httpclient.NewRequest("POST", "/api/v2/workflow/%s/approve/%s",
+		httpclient.RouteParams(url.PathEscape(workflowID), url.PathEscape(approvalRequestID)),
+	)
+	if err := s.circleci.Call(ctx, req); err != nil {
+		return fmt.Errorf("unable to approve the workflow %s: %w", workflowID, err)
+	}
+	return nil
+}
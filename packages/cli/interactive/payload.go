@@ -0,0 +1,64 @@
+package interactive
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// TypeBlockActions is the type of the payloads sent when a button of a message is clicked.
+const TypeBlockActions = "block_actions"
+
+// Payload is the part of an interaction payload that is used to dispatch the actions.
+// See https://api.slack.com/reference/interaction-payloads/block-actions.
+type Payload struct {
+	Type        string        `json:"type"`
+	User        User          `json:"user"`
+	Channel     Channel       `json:"channel"`
+	Message     Message       `json:"message"`
+	ResponseURL string        `json:"response_url"`
+	Actions     []BlockAction `json:"actions"`
+}
+
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+type Channel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Message struct {
+	TS string `json:"ts"`
+}
+
+// BlockAction is a click on an interactive element, such as a button.
+type BlockAction struct {
+	ActionID string `json:"action_id"`
+	BlockID  string `json:"block_id"`
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+	ActionTS string `json:"action_ts"`
+}
+
+// parsePayload decodes the form encoded body Slack posts to the request URL,
+// whose payload field holds the JSON payload.
+func parsePayload(body []byte) (*Payload, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid form body: %w", err)
+	}
+	raw := form.Get("payload")
+	if raw == "" {
+		return nil, errors.New("the request has no payload")
+	}
+
+	var payload Payload
+	if err := json.Unmarshal([]byte(raw), &payload); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	return &payload, nil
+}
@@ -0,0 +1,159 @@
+// Package interactive receives the callbacks Slack sends when the buttons of a notification
+// are clicked, and runs the action configured for each button.
+package interactive
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/circleci/ex/config/secret"
+	"github.com/circleci/ex/httpclient"
+	"github.com/circleci/ex/httpserver/ginrouter"
+	"github.com/gin-gonic/gin"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/signing"
+)
+
+// ActionsPath is the path of the request URL to configure in the Interactivity settings of the Slack app.
+const ActionsPath = "/slack/actions"
+
+const (
+	defaultCircleCIURL = "https://circleci.com"
+	// actionTimeout bounds each action, since they run after Slack was answered.
+	actionTimeout = 5 * time.Minute
+)
+
+type Options struct {
+	// SigningSecret is the signing secret of the Slack app, used to verify the requests.
+	SigningSecret secret.String
+	// Actions maps action IDs to the action run when the button is clicked.
+	Actions map[string]Action
+	// CircleCIBaseURL is the URL of the CircleCI API used to approve hold jobs.
+	CircleCIBaseURL string
+	CircleCIToken   secret.String
+	// Logger receives the outcome of the actions. They are discarded when it is nil.
+	Logger *log.Logger
+}
+
+type Server struct {
+	secret   secret.String
+	actions  map[string]Action
+	circleci *httpclient.Client
+	webhooks *httpclient.Client
+	logger   *log.Logger
+	now      func() time.Time
+
+	wg sync.WaitGroup
+}
+
+func New(options Options) (*Server, error) {
+	if options.SigningSecret.Value() == "" {
+		return nil, errors.New("the signing secret must not be empty")
+	}
+	if len(options.Actions) == 0 {
+		return nil, errors.New("no action is configured")
+	}
+	for id, action := range options.Actions {
+		if action.Type == TypeCircleCIApproval && options.CircleCIToken.Value() == "" {
+			return nil, errors.New("a CircleCI token is required by the approval action " + id)
+		}
+	}
+
+	baseURL := defaultCircleCIURL
+	if options.CircleCIBaseURL != "" {
+		baseURL = options.CircleCIBaseURL
+	}
+	logger := options.Logger
+	if logger == nil {
+		logger = log.New(io.Discard)
+	}
+
+	return &Server{
+		secret:  options.SigningSecret,
+		actions: options.Actions,
+		circleci: httpclient.New(httpclient.Config{
+			Name:       "CircleCI Client",
+			BaseURL:    baseURL,
+			AuthHeader: "Circle-Token",
+			AuthToken:  options.CircleCIToken.Value(),
+			AcceptType: httpclient.JSON,
+			Timeout:    time.Second * 30,
+		}),
+		webhooks: httpclient.New(httpclient.Config{
+			Name:    "Webhook Client",
+			Timeout: time.Second * 30,
+		}),
+		logger: logger,
+		now:    time.Now,
+	}, nil
+}
+
+// Handler returns the handler of the request URL.
+func (s *Server) Handler(ctx context.Context) http.Handler {
+	r := ginrouter.Default(ctx, "slack-orb-interactive")
+	r.POST(ActionsPath, s.handleActions)
+	return r
+}
+
+// Wait waits for the actions that are still running.
+func (s *Server) Wait() {
+	s.wg.Wait()
+}
+
+// handleActions verifies and answers the request, then runs the actions in the background,
+// since Slack expects an answer within 3 seconds.
+func (s *Server) handleActions(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if err := signing.VerifyHeaders(c.Request.Header, s.secret.Value(), body, s.now()); err != nil {
+		s.logger.Warnf("Rejected a request: %v", err)
+		c.Status(http.StatusUnauthorized)
+		return
+	}
+
+	payload, err := parsePayload(body)
+	if err != nil {
+		s.logger.Warnf("Rejected a request: %v", err)
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if payload.Type != TypeBlockActions {
+		s.logger.Debugf("Ignored a %s payload", payload.Type)
+		c.Status(http.StatusOK)
+		return
+	}
+
+	for _, blockAction := range payload.Actions {
+		action, ok := s.actions[blockAction.ActionID]
+		if !ok {
+			s.logger.Debugf("No action is configured for %s", blockAction.ActionID)
+			continue
+		}
+		s.dispatch(action, newEvent(payload, blockAction))
+	}
+	c.Status(http.StatusOK)
+}
+
+func (s *Server) dispatch(action Action, event Event) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), actionTimeout)
+		defer cancel()
+
+		s.logger.Infof("%s clicked %s", event.Username, event.ActionID)
+		if err := s.run(ctx, action, event); err != nil {
+			s.logger.Errorf("The %s action for %s failed: %v", action.Type, event.ActionID, err)
+			return
+		}
+		s.logger.Infof("The %s action for %s succeeded", action.Type, event.ActionID)
+	}()
+}
@@ -0,0 +1,173 @@
+package interactive
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/circleci/ex/config/secret"
+	"github.com/circleci/ex/testing/testcontext"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/internal/testing/fakeslack"
+)
+
+const testSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+func TestLoadActions(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("valid", func(t *testing.T) {
+		path := filepath.Join(dir, "actions.json")
+		assert.NilError(t, os.WriteFile(path, []byte(`{
+			"approve": {"type": "circleci-approval"},
+			"rollback": {"type": "command", "command": "./rollback.sh"}
+		}`), 0o600))
+
+		actions, err := LoadActions(path)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.DeepEqual(actions, map[string]Action{
+			"approve":  {Type: TypeCircleCIApproval},
+			"rollback": {Type: TypeCommand, Command: "./rollback.sh"},
+		}))
+	})
+
+	t.Run("webhook without url", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid.json")
+		assert.NilError(t, os.WriteFile(path, []byte(`{"audit": {"type": "webhook"}}`), 0o600))
+
+		_, err := LoadActions(path)
+		assert.Check(t, cmp.Error(err, `action "audit": a webhook action must have a url`))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadActions(filepath.Join(dir, "missing.json"))
+		assert.Check(t, cmp.ErrorContains(err, "unable to read the actions file"))
+	})
+}
+
+func TestServer(t *testing.T) {
+	ctx := testcontext.Background()
+	dir := t.TempDir()
+
+	var (
+		mu       sync.Mutex
+		approved []string
+		webhook  Event
+	)
+	circleci := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		approved = append(approved, r.Header.Get("Circle-Token")+" "+r.URL.Path)
+		_, _ = w.Write([]byte(`{"message": "Accepted."}`))
+	}))
+	t.Cleanup(circleci.Close)
+	hooks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &webhook)
+	}))
+	t.Cleanup(hooks.Close)
+
+	s, err := New(Options{
+		SigningSecret: secret.String(testSecret),
+		Actions: map[string]Action{
+			"approve":  {Type: TypeCircleCIApproval},
+			"rollback": {Type: TypeCommand, Command: `echo "$SLACK_USERNAME $SLACK_ACTION_VALUE" > ` + filepath.Join(dir, "out")},
+			"audit":    {Type: TypeWebhook, URL: hooks.URL + "/audit"},
+		},
+		CircleCIBaseURL: circleci.URL,
+		CircleCIToken:   secret.String("circle-token"),
+	})
+	assert.NilError(t, err)
+	server := httptest.NewServer(s.Handler(ctx))
+	t.Cleanup(server.Close)
+
+	post := func(t *testing.T, payload string, signed bool) int {
+		t.Helper()
+		defer s.Wait()
+		if signed {
+			status, err := fakeslack.SendInteraction(ctx, server.URL+ActionsPath, testSecret, json.RawMessage(payload))
+			assert.NilError(t, err)
+			return status
+		}
+		body := url.Values{"payload": {payload}}.Encode()
+		resp, err := http.Post(server.URL+ActionsPath, "application/x-www-form-urlencoded", strings.NewReader(body))
+		assert.NilError(t, err)
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	t.Run("approval", func(t *testing.T) {
+		status := post(t, `{"type": "block_actions", "user": {"id": "U1", "username": "alice"},
+			"actions": [{"action_id": "approve", "value": "wf-1/job-2"}]}`, true)
+		assert.Check(t, cmp.Equal(status, http.StatusOK))
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Check(t, cmp.DeepEqual(approved, []string{"circle-token /api/v2/workflow/wf-1/approve/job-2"}))
+	})
+
+	t.Run("command", func(t *testing.T) {
+		status := post(t, `{"type": "block_actions", "user": {"id": "U1", "username": "alice"},
+			"actions": [{"action_id": "rollback", "value": "v1.2.3"}]}`, true)
+		assert.Check(t, cmp.Equal(status, http.StatusOK))
+		out, err := os.ReadFile(filepath.Join(dir, "out"))
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(string(out), "alice v1.2.3\n"))
+	})
+
+	t.Run("webhook", func(t *testing.T) {
+		status := post(t, `{"type": "block_actions", "user": {"id": "U1", "username": "alice"},
+			"channel": {"id": "C1"}, "message": {"ts": "1700000000.000100"},
+			"actions": [{"action_id": "audit", "value": "v1.2.3"}]}`, true)
+		assert.Check(t, cmp.Equal(status, http.StatusOK))
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Check(t, cmp.DeepEqual(webhook, Event{
+			ActionID:  "audit",
+			Value:     "v1.2.3",
+			UserID:    "U1",
+			Username:  "alice",
+			ChannelID: "C1",
+			MessageTS: "1700000000.000100",
+		}))
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		status := post(t, `{"type": "block_actions", "actions": [{"action_id": "approve", "value": "wf-9/job-9"}]}`, false)
+		assert.Check(t, cmp.Equal(status, http.StatusUnauthorized))
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Check(t, cmp.Len(approved, 1))
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		status := post(t, `{"type": "block_actions", "actions": [{"action_id": "view_job"}]}`, true)
+		assert.Check(t, cmp.Equal(status, http.StatusOK))
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		status := post(t, `"not json"`, true)
+		assert.Check(t, cmp.Equal(status, http.StatusBadRequest))
+	})
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Options{Actions: map[string]Action{"rollback": {Type: TypeCommand, Command: "true"}}})
+	assert.Check(t, cmp.Error(err, "the signing secret must not be empty"))
+
+	_, err = New(Options{
+		SigningSecret: secret.String(testSecret),
+		Actions:       map[string]Action{"approve": {Type: TypeCircleCIApproval}},
+	})
+	assert.Check(t, cmp.Error(err, "a CircleCI token is required by the approval action approve"))
+}
@@ -3,20 +3,63 @@ package fakeslack
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/circleci/ex/httpserver/ginrouter"
 	"github.com/circleci/ex/testing/httprecorder"
 	"github.com/circleci/ex/testing/httprecorder/ginrecorder"
 	"github.com/gin-gonic/gin"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/signing"
 )
 
+// UnknownChannel is rejected with channel_not_found, to simulate delivery errors.
+const UnknownChannel = "unknown-channel"
+
+// SlowChannel never gets an answer to chat.postMessage until the request is canceled,
+// to simulate timeouts.
+const SlowChannel = "slow-channel"
+
+// OutsideUser is not a member of any channel, so ephemeral messages for that user are rejected with user_not_in_channel.
+const OutsideUser = "U0OUTSIDE"
+
+// UnknownEmail is rejected by users.lookupByEmail with users_not_found.
+// The ID of any other user is "U0" followed by the upper-cased local part of the email address.
+const UnknownEmail = "nobody@example.com"
+
+// UserGroup is the handle of the only user group, whose members are UserGroupMembers.
+const UserGroup = "oncall"
+
+var UserGroupMembers = []string{"U0ONCALL1", "U0ONCALL2"}
+
 type API struct {
 	*httprecorder.RequestRecorder
 	router *gin.Engine
 
-	mu sync.RWMutex
+	mu        sync.RWMutex
+	messages  int
+	scheduled map[string]string
+	uploads   []*Upload
+	// reactions maps "<channel>/<ts>" to the names of the reactions on the message.
+	reactions map[string][]string
+	// deleted holds the "<channel>/<ts>" of the deleted messages.
+	deleted map[string]bool
+}
+
+// Upload is a file uploaded with the external upload flow.
+type Upload struct {
+	FileID    string
+	Filename  string
+	Content   []byte
+	ChannelID string
+	ThreadTS  string
+	// Completed is set once files.completeUploadExternal shared the file.
+	Completed bool
 }
 
 type APIRequest struct {
@@ -30,9 +73,69 @@ type Message struct {
 }
 
 type APIResponse struct {
-	Error   string  `json:"error"`
-	Ok      bool    `json:"ok"`
-	Message Message `json:"message"`
+	Error              string  `json:"error"`
+	Ok                 bool    `json:"ok"`
+	Channel            string  `json:"channel,omitempty"`
+	TS                 string  `json:"ts,omitempty"`
+	Permalink          string  `json:"permalink,omitempty"`
+	ScheduledMessageID string  `json:"scheduled_message_id,omitempty"`
+	PostAt             int64   `json:"post_at,omitempty"`
+	UploadURL          string  `json:"upload_url,omitempty"`
+	FileID             string  `json:"file_id,omitempty"`
+	MessageTS          string  `json:"message_ts,omitempty"`
+	Message            Message `json:"message"`
+}
+
+type Conversation struct {
+	ID string `json:"id"`
+}
+
+type ConversationResponse struct {
+	Error   string       `json:"error,omitempty"`
+	Ok      bool         `json:"ok"`
+	Channel Conversation `json:"channel"`
+}
+
+type UserResponse struct {
+	Error string `json:"error,omitempty"`
+	Ok    bool   `json:"ok"`
+	User  User   `json:"user"`
+}
+
+type User struct {
+	ID string `json:"id"`
+}
+
+type UserGroupsResponse struct {
+	Error      string          `json:"error,omitempty"`
+	Ok         bool            `json:"ok"`
+	UserGroups []UserGroupInfo `json:"usergroups,omitempty"`
+	Users      []string        `json:"users,omitempty"`
+}
+
+type UserGroupInfo struct {
+	ID     string `json:"id"`
+	Handle string `json:"handle"`
+}
+
+type ScheduleRequest struct {
+	Channel            string `json:"channel"`
+	PostAt             int64  `json:"post_at"`
+	ScheduledMessageID string `json:"scheduled_message_id"`
+}
+
+type ReactionRequest struct {
+	Channel   string `json:"channel"`
+	Timestamp string `json:"timestamp"`
+	Name      string `json:"name"`
+}
+
+type CompleteUploadRequest struct {
+	Files []struct {
+		ID string `json:"id"`
+	} `json:"files"`
+	ChannelID string `json:"channel_id"`
+	ThreadTS  string `json:"thread_ts"`
 }
 
 func New(ctx context.Context) *API {
@@ -41,6 +144,14 @@ func New(ctx context.Context) *API {
 	// record all requests
 	r.Use(ginrecorder.Middleware(ctx, rec))
 
+	f := &API{
+		RequestRecorder: rec,
+		router:          r,
+		scheduled:       map[string]string{},
+		reactions:       map[string][]string{},
+		deleted:         map[string]bool{},
+	}
+
 	r.POST("chat.postMessage", func(c *gin.Context) {
 		if c.Request.Header.Get("Content-Type") == "" {
 			c.JSON(http.StatusBadRequest, struct{ Error string }{
@@ -53,17 +164,419 @@ func New(ctx context.Context) *API {
 			c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
 		}
 
+		if request.Channel == UnknownChannel {
+			c.JSON(http.StatusOK, APIResponse{Error: "channel_not_found"})
+			return
+		}
+		if request.Channel == SlowChannel {
+			select {
+			case <-c.Request.Context().Done():
+			case <-time.After(time.Minute):
+			}
+			return
+		}
+
 		c.JSON(http.StatusOK, APIResponse{
+			Ok:      true,
+			Channel: request.Channel,
+			TS:      f.nextTS(),
 			Message: Message{Type: "message", Text: string(request.Message)},
 		})
 	})
 
-	return &API{
-		RequestRecorder: rec,
-		router:          r,
+	r.POST("chat.postEphemeral", func(c *gin.Context) {
+		var request struct {
+			Channel string `json:"channel"`
+			User    string `json:"user"`
+		}
+		err := json.Unmarshal(rec.LastRequest().Body, &request)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+			return
+		}
+		switch {
+		case request.Channel == UnknownChannel:
+			c.JSON(http.StatusOK, APIResponse{Error: "channel_not_found"})
+		case request.User == OutsideUser:
+			c.JSON(http.StatusOK, APIResponse{Error: "user_not_in_channel"})
+		default:
+			c.JSON(http.StatusOK, APIResponse{Ok: true, MessageTS: f.nextTS()})
+		}
+	})
+
+	r.POST("conversations.open", func(c *gin.Context) {
+		var request struct {
+			Users string `json:"users"`
+		}
+		err := json.Unmarshal(rec.LastRequest().Body, &request)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+			return
+		}
+		if request.Users == "" {
+			c.JSON(http.StatusOK, APIResponse{Error: "users_list_not_supplied"})
+			return
+		}
+
+		c.JSON(http.StatusOK, ConversationResponse{
+			Ok:      true,
+			Channel: Conversation{ID: "D" + strings.ReplaceAll(request.Users, ",", "")},
+		})
+	})
+
+	r.GET("users.lookupByEmail", func(c *gin.Context) {
+		email := c.Query("email")
+		name, _, ok := strings.Cut(email, "@")
+		if !ok || email == UnknownEmail {
+			c.JSON(http.StatusOK, UserResponse{Error: "users_not_found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, UserResponse{Ok: true, User: User{ID: "U0" + strings.ToUpper(name)}})
+	})
+
+	r.GET("usergroups.list", func(c *gin.Context) {
+		c.JSON(http.StatusOK, UserGroupsResponse{
+			Ok:         true,
+			UserGroups: []UserGroupInfo{{ID: "S0ONCALL", Handle: UserGroup}},
+		})
+	})
+
+	r.GET("usergroups.users.list", func(c *gin.Context) {
+		if c.Query("usergroup") != "S0ONCALL" {
+			c.JSON(http.StatusOK, UserGroupsResponse{Error: "no_such_subteam"})
+			return
+		}
+
+		c.JSON(http.StatusOK, UserGroupsResponse{Ok: true, Users: UserGroupMembers})
+	})
+
+	r.POST("chat.update", func(c *gin.Context) {
+		var request struct {
+			Channel string `json:"channel"`
+			TS      string `json:"ts"`
+		}
+		err := json.Unmarshal(rec.LastRequest().Body, &request)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+			return
+		}
+		if request.Channel == "" || request.TS == "" {
+			c.JSON(http.StatusOK, APIResponse{Error: "message_not_found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, APIResponse{Ok: true, Channel: request.Channel, TS: request.TS})
+	})
+
+	r.POST("chat.delete", func(c *gin.Context) {
+		var request struct {
+			Channel string `json:"channel"`
+			TS      string `json:"ts"`
+		}
+		err := json.Unmarshal(rec.LastRequest().Body, &request)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+			return
+		}
+		if request.Channel == UnknownChannel {
+			c.JSON(http.StatusOK, APIResponse{Error: "channel_not_found"})
+			return
+		}
+		if !f.delete(request.Channel, request.TS) {
+			c.JSON(http.StatusOK, APIResponse{Error: "message_not_found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, APIResponse{Ok: true, Channel: request.Channel, TS: request.TS})
+	})
+
+	r.GET("chat.getPermalink", func(c *gin.Context) {
+		channel := c.Query("channel")
+		ts := c.Query("message_ts")
+		if channel == "" || ts == "" {
+			c.JSON(http.StatusOK, APIResponse{Error: "invalid_arguments"})
+			return
+		}
+
+		c.JSON(http.StatusOK, APIResponse{
+			Ok:        true,
+			Channel:   channel,
+			Permalink: fmt.Sprintf("https://fake-slack.example.com/archives/%s/p%s", channel, strings.ReplaceAll(ts, ".", "")),
+		})
+	})
+
+	r.POST("chat.scheduleMessage", func(c *gin.Context) {
+		var request ScheduleRequest
+		err := json.Unmarshal(rec.LastRequest().Body, &request)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+			return
+		}
+		if request.Channel == UnknownChannel {
+			c.JSON(http.StatusOK, APIResponse{Error: "channel_not_found"})
+			return
+		}
+		if request.PostAt <= time.Now().Unix() {
+			c.JSON(http.StatusOK, APIResponse{Error: "time_in_past"})
+			return
+		}
+
+		c.JSON(http.StatusOK, APIResponse{
+			Ok:                 true,
+			Channel:            request.Channel,
+			ScheduledMessageID: f.schedule(request.Channel),
+			PostAt:             request.PostAt,
+		})
+	})
+
+	r.POST("chat.deleteScheduledMessage", func(c *gin.Context) {
+		var request ScheduleRequest
+		err := json.Unmarshal(rec.LastRequest().Body, &request)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+			return
+		}
+		if !f.unschedule(request.Channel, request.ScheduledMessageID) {
+			c.JSON(http.StatusOK, APIResponse{Error: "invalid_scheduled_message_id"})
+			return
+		}
+
+		c.JSON(http.StatusOK, APIResponse{Ok: true})
+	})
+
+	r.POST("reactions.add", func(c *gin.Context) {
+		var request ReactionRequest
+		err := json.Unmarshal(rec.LastRequest().Body, &request)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+			return
+		}
+		if !f.react(request, true) {
+			c.JSON(http.StatusOK, APIResponse{Error: "already_reacted"})
+			return
+		}
+
+		c.JSON(http.StatusOK, APIResponse{Ok: true})
+	})
+
+	r.POST("reactions.remove", func(c *gin.Context) {
+		var request ReactionRequest
+		err := json.Unmarshal(rec.LastRequest().Body, &request)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+			return
+		}
+		if !f.react(request, false) {
+			c.JSON(http.StatusOK, APIResponse{Error: "no_reaction"})
+			return
+		}
+
+		c.JSON(http.StatusOK, APIResponse{Ok: true})
+	})
+
+	r.POST("files.getUploadURLExternal", func(c *gin.Context) {
+		form, err := url.ParseQuery(string(rec.LastRequest().Body))
+		if err != nil || form.Get("filename") == "" || form.Get("length") == "" {
+			c.JSON(http.StatusOK, APIResponse{Error: "invalid_arguments"})
+			return
+		}
+
+		id := f.newUpload(form.Get("filename"))
+		c.JSON(http.StatusOK, APIResponse{
+			Ok:        true,
+			UploadURL: "http://" + c.Request.Host + "/upload/" + id,
+			FileID:    id,
+		})
+	})
+
+	r.POST("upload/:file_id", func(c *gin.Context) {
+		upload := f.upload(c.Param("file_id"))
+		if upload == nil {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		f.mu.Lock()
+		upload.Content = rec.LastRequest().Body
+		f.mu.Unlock()
+		c.String(http.StatusOK, "OK - %d", len(upload.Content))
+	})
+
+	r.POST("files.completeUploadExternal", func(c *gin.Context) {
+		var request CompleteUploadRequest
+		err := json.Unmarshal(rec.LastRequest().Body, &request)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, APIResponse{Error: err.Error()})
+			return
+		}
+		if request.ChannelID == UnknownChannel {
+			c.JSON(http.StatusOK, APIResponse{Error: "channel_not_found"})
+			return
+		}
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		for _, file := range request.Files {
+			upload := f.findUpload(file.ID)
+			if upload == nil {
+				c.JSON(http.StatusOK, APIResponse{Error: "file_not_found"})
+				return
+			}
+			upload.ChannelID = request.ChannelID
+			upload.ThreadTS = request.ThreadTS
+			upload.Completed = true
+		}
+		c.JSON(http.StatusOK, APIResponse{Ok: true})
+	})
+
+	return f
+}
+
+// Uploads returns the files uploaded so far.
+func (f *API) Uploads() []Upload {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	uploads := make([]Upload, 0, len(f.uploads))
+	for _, upload := range f.uploads {
+		uploads = append(uploads, *upload)
 	}
+	return uploads
+}
+
+func (f *API) newUpload(filename string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := fmt.Sprintf("F%08d", len(f.uploads)+1)
+	f.uploads = append(f.uploads, &Upload{FileID: id, Filename: filename})
+	return id
+}
+
+func (f *API) upload(id string) *Upload {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.findUpload(id)
+}
+
+// findUpload must be called with the lock held.
+func (f *API) findUpload(id string) *Upload {
+	for _, upload := range f.uploads {
+		if upload.FileID == id {
+			return upload
+		}
+	}
+	return nil
+}
+
+// Deleted reports whether the message identified by channel and ts was deleted.
+func (f *API) Deleted(channel, ts string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.deleted[channel+"/"+ts]
+}
+
+// delete deletes the message, and reports whether it was not deleted yet.
+func (f *API) delete(channel, ts string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := channel + "/" + ts
+	if ts == "" || f.deleted[key] {
+		return false
+	}
+	f.deleted[key] = true
+	return true
+}
+
+// Reactions returns the names of the reactions on the message identified by channel and ts.
+func (f *API) Reactions(channel, ts string) []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return append([]string{}, f.reactions[channel+"/"+ts]...)
+}
+
+// react adds or removes the reaction, and reports whether the reactions of the message changed.
+func (f *API) react(request ReactionRequest, add bool) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := request.Channel + "/" + request.Timestamp
+	reactions := f.reactions[key]
+	for i, name := range reactions {
+		if name == request.Name {
+			if add {
+				return false
+			}
+			f.reactions[key] = append(reactions[:i], reactions[i+1:]...)
+			return true
+		}
+	}
+	if !add {
+		return false
+	}
+	f.reactions[key] = append(reactions, request.Name)
+	return true
+}
+
+// Scheduled returns the IDs of the messages that are scheduled and not yet cancelled.
+func (f *API) Scheduled() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	ids := make([]string, 0, len(f.scheduled))
+	for id := range f.scheduled {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (f *API) schedule(channel string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages++
+	id := fmt.Sprintf("Q%08d", f.messages)
+	f.scheduled[id] = channel
+	return id
+}
+
+func (f *API) unschedule(channel, id string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.scheduled[id] != channel {
+		return false
+	}
+	delete(f.scheduled, id)
+	return true
+}
+
+// nextTS returns a unique message timestamp in the format Slack uses.
+func (f *API) nextTS() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages++
+	return fmt.Sprintf("1700000000.%06d", f.messages)
 }
 
 func (f *API) Handler() http.Handler {
 	return f.router
 }
+
+// SendInteraction posts payload to the request URL of an app, signed with its signing secret,
+// as Slack does when a button is clicked. It returns the status code of the response.
+func SendInteraction(ctx context.Context, requestURL, secret string, payload interface{}) (int, error) {
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+	body := url.Values{"payload": {string(content)}}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, strings.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	signing.SetHeaders(req.Header, secret, []byte(body), time.Now())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode, nil
+}
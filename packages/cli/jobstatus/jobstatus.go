@@ -0,0 +1,114 @@
+// Package jobstatus records the status of a CI job in a state file, so that a notification sent
+// in a later step, such as one running with "when: always", knows whether the job failed.
+//
+// The state file is a dotenv file with a single CCI_STATUS variable, set to "pass" or "fail":
+//
+//	CCI_STATUS="fail"
+//
+// It is the format written by the orb, so files written by either can be read by the other.
+package jobstatus
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/joho/godotenv"
+)
+
+// The statuses that can be recorded.
+const (
+	Pass = "pass"
+	Fail = "fail"
+)
+
+// EnvVar is the variable holding the status, in the state file and in the environment.
+const EnvVar = "CCI_STATUS"
+
+// ErrNotRecorded is returned by Read when no status was recorded.
+var ErrNotRecorded = errors.New("no job status was recorded")
+
+// Path returns the state file named by SLACK_STR_STATUS_FILE, or the one used by the orb.
+// On Windows, it is in the temporary directory of the current user, which is /tmp in Git Bash.
+func Path(getenv func(string) string) string {
+	if path := getenv("SLACK_STR_STATUS_FILE"); path != "" {
+		return path
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.TempDir(), "SLACK_JOB_STATUS")
+	}
+	return "/tmp/SLACK_JOB_STATUS"
+}
+
+// Validate checks that status is one of Pass or Fail.
+func Validate(status string) error {
+	if status != Pass && status != Fail {
+		return fmt.Errorf("invalid job status %q: must be one of %q or %q", status, Pass, Fail)
+	}
+	return nil
+}
+
+// Read returns the status recorded in the state file at path.
+// It returns ErrNotRecorded when the file does not exist.
+func Read(path string) (string, error) {
+	vars, err := godotenv.Read(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrNotRecorded
+	}
+	if err != nil {
+		return "", fmt.Errorf("error reading the job status from %q: %w", path, err)
+	}
+
+	status, ok := vars[EnvVar]
+	if !ok {
+		return "", fmt.Errorf("error reading the job status from %q: %s is not set", path, EnvVar)
+	}
+	if err := Validate(status); err != nil {
+		return "", fmt.Errorf("error reading the job status from %q: %w", path, err)
+	}
+	return status, nil
+}
+
+// Record writes status to the state file at path and returns the status the file now holds.
+// A recorded failure is kept unless overwrite is set, so that the status can be recorded
+// by a step running with "when: always" after the one recording failures with "when: on_fail".
+func Record(path, status string, overwrite bool) (string, error) {
+	if err := Validate(status); err != nil {
+		return "", err
+	}
+
+	if !overwrite {
+		recorded, err := Read(path)
+		if err != nil && !errors.Is(err, ErrNotRecorded) {
+			return "", err
+		}
+		if recorded == Fail {
+			return Fail, nil
+		}
+	}
+
+	content := fmt.Sprintf("%s=%q\n", EnvVar, status)
+	//nolint:gosec // the state file is read by other steps of the job
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return "", fmt.Errorf("error recording the job status in %q: %w", path, err)
+	}
+	return status, nil
+}
+
+// Load sets CCI_STATUS to the status recorded in the state file at path, unless it is already set.
+// Nothing is done when no status was recorded.
+func Load(path string) error {
+	if os.Getenv(EnvVar) != "" {
+		return nil
+	}
+	status, err := Read(path)
+	if errors.Is(err, ErrNotRecorded) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return os.Setenv(EnvVar, status)
+}
@@ -0,0 +1,126 @@
+package jobstatus
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func TestRecord(t *testing.T) {
+	t.Run("records a status", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "SLACK_JOB_STATUS")
+		recorded, err := Record(path, Pass, false)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(recorded, Pass))
+
+		content, err := os.ReadFile(path)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(string(content), "CCI_STATUS=\"pass\"\n"))
+	})
+
+	t.Run("keeps a recorded failure", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "SLACK_JOB_STATUS")
+		_, err := Record(path, Fail, false)
+		assert.NilError(t, err)
+
+		recorded, err := Record(path, Pass, false)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(recorded, Fail))
+		status, err := Read(path)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(status, Fail))
+	})
+
+	t.Run("overwrites a recorded failure", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "SLACK_JOB_STATUS")
+		_, err := Record(path, Fail, false)
+		assert.NilError(t, err)
+
+		recorded, err := Record(path, Pass, true)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(recorded, Pass))
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "SLACK_JOB_STATUS")
+		_, err := Record(path, "success", false)
+		assert.Check(t, cmp.ErrorContains(err, `invalid job status "success"`))
+		_, err = os.Stat(path)
+		assert.Check(t, os.IsNotExist(err))
+	})
+}
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		assert.NilError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		expected    string
+		expectedErr string
+	}{
+		{name: "written by the orb", path: write("orb", "CCI_STATUS=\"fail\"\n"), expected: Fail},
+		{name: "CRLF line endings", path: write("crlf", "CCI_STATUS=\"pass\"\r\n"), expected: Pass},
+		{name: "unquoted", path: write("unquoted", "CCI_STATUS=pass"), expected: Pass},
+		{name: "missing file", path: filepath.Join(dir, "missing"), expectedErr: ErrNotRecorded.Error()},
+		{name: "missing variable", path: write("empty", "OTHER=1\n"), expectedErr: "CCI_STATUS is not set"},
+		{name: "invalid status", path: write("invalid", "CCI_STATUS=unknown\n"), expectedErr: `invalid job status "unknown"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := Read(tt.path)
+			if tt.expectedErr != "" {
+				assert.Check(t, cmp.ErrorContains(err, tt.expectedErr))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, cmp.Equal(status, tt.expected))
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "SLACK_JOB_STATUS")
+	_, err := Record(path, Fail, false)
+	assert.NilError(t, err)
+
+	t.Run("sets the status", func(t *testing.T) {
+		t.Setenv(EnvVar, "")
+		assert.NilError(t, Load(path))
+		assert.Check(t, cmp.Equal(os.Getenv(EnvVar), Fail))
+	})
+
+	t.Run("keeps the environment", func(t *testing.T) {
+		t.Setenv(EnvVar, Pass)
+		assert.NilError(t, Load(path))
+		assert.Check(t, cmp.Equal(os.Getenv(EnvVar), Pass))
+	})
+
+	t.Run("nothing recorded", func(t *testing.T) {
+		t.Setenv(EnvVar, "")
+		assert.NilError(t, Load(filepath.Join(t.TempDir(), "missing")))
+		assert.Check(t, cmp.Equal(os.Getenv(EnvVar), ""))
+	})
+}
+
+func TestPath(t *testing.T) {
+	getenv := func(string) string { return "/custom/status" }
+	assert.Check(t, cmp.Equal(Path(getenv), "/custom/status"))
+
+	noenv := func(string) string { return "" }
+	if runtime.GOOS == "windows" {
+		assert.Check(t, cmp.Equal(Path(noenv), filepath.Join(os.TempDir(), "SLACK_JOB_STATUS")))
+	} else {
+		assert.Check(t, cmp.Equal(Path(noenv), "/tmp/SLACK_JOB_STATUS"))
+	}
+}
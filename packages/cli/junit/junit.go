@@ -0,0 +1,188 @@
+// Package junit summarizes JUnit XML test reports for use in notification templates.
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// maxMessageLength caps failure messages so that a summary fits in a Slack block.
+const maxMessageLength = 150
+
+// Summary is the aggregated outcome of the test cases in one or more reports.
+type Summary struct {
+	Total    int
+	Failed   int
+	Skipped  int
+	Failures []Failure
+}
+
+// Failure is a test case that failed or errored.
+type Failure struct {
+	Name    string
+	Message string
+}
+
+// Passed returns the number of test cases that neither failed nor were skipped.
+func (s *Summary) Passed() int {
+	return s.Total - s.Failed - s.Skipped
+}
+
+type testSuite struct {
+	TestSuites []testSuite `xml:"testsuite"`
+	TestCases  []testCase  `xml:"testcase"`
+}
+
+type testCase struct {
+	Name      string   `xml:"name,attr"`
+	Classname string   `xml:"classname,attr"`
+	Failure   *outcome `xml:"failure"`
+	Error     *outcome `xml:"error"`
+	Skipped   *outcome `xml:"skipped"`
+}
+
+type outcome struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// Load summarizes the reports at path, which is either a report, a directory
+// searched recursively for .xml files, or a glob pattern.
+func Load(path string) (*Summary, error) {
+	files, err := reportFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no JUnit reports found at %q", path)
+	}
+
+	s := &Summary{}
+	for _, file := range files {
+		if err := s.addFile(file); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func reportFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		// Not an existing file or directory, so try it as a glob pattern
+		return filepath.Glob(path)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.EqualFold(filepath.Ext(p), ".xml") {
+			files = append(files, p)
+		}
+		return nil
+	})
+	return files, err
+}
+
+func (s *Summary) addFile(path string) error {
+	//nolint:gosec // G304 the path is provided by the user on purpose
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read JUnit report: %w", err)
+	}
+
+	// The root element is either <testsuites> or a single <testsuite>, and both are decoded the same way
+	var root testSuite
+	if err := xml.Unmarshal(content, &root); err != nil {
+		return fmt.Errorf("unable to parse JUnit report %q: %w", path, err)
+	}
+	s.addSuite(root)
+	return nil
+}
+
+func (s *Summary) addSuite(suite testSuite) {
+	for _, tc := range suite.TestCases {
+		s.Total++
+		switch {
+		case tc.Failure != nil:
+			s.addFailure(tc, tc.Failure)
+		case tc.Error != nil:
+			s.addFailure(tc, tc.Error)
+		case tc.Skipped != nil:
+			s.Skipped++
+		}
+	}
+	for _, child := range suite.TestSuites {
+		s.addSuite(child)
+	}
+}
+
+func (s *Summary) addFailure(tc testCase, o *outcome) {
+	s.Failed++
+
+	name := tc.Name
+	if tc.Classname != "" {
+		name = tc.Classname + "." + tc.Name
+	}
+	message := o.Message
+	if message == "" {
+		message = o.Text
+	}
+	s.Failures = append(s.Failures, Failure{Name: name, Message: firstLine(message)})
+}
+
+// firstLine returns the first non-empty line of message, truncated to maxMessageLength.
+func firstLine(message string) string {
+	for _, line := range strings.Split(message, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			if len([]rune(line)) > maxMessageLength {
+				line = string([]rune(line)[:maxMessageLength-1]) + "…"
+			}
+			return line
+		}
+	}
+	return ""
+}
+
+// EnvVars returns the built-in variables describing the summary. At most maxFailures
+// failing tests are listed in SLACK_ORB_TEST_FAILURES.
+func (s *Summary) EnvVars(maxFailures int) map[string]string {
+	return map[string]string{
+		"SLACK_ORB_TESTS_TOTAL":   strconv.Itoa(s.Total),
+		"SLACK_ORB_TESTS_PASSED":  strconv.Itoa(s.Passed()),
+		"SLACK_ORB_TESTS_FAILED":  strconv.Itoa(s.Failed),
+		"SLACK_ORB_TESTS_SKIPPED": strconv.Itoa(s.Skipped),
+		"SLACK_ORB_TEST_FAILURES": s.failureList(maxFailures),
+	}
+}
+
+// failureList formats the failing tests as a mrkdwn bullet list.
+func (s *Summary) failureList(maxFailures int) string {
+	if len(s.Failures) == 0 {
+		return "No failing tests"
+	}
+
+	var b strings.Builder
+	for i, f := range s.Failures {
+		if i == maxFailures {
+			fmt.Fprintf(&b, "…and %d more", len(s.Failures)-maxFailures)
+			break
+		}
+		fmt.Fprintf(&b, "• `%s`", f.Name)
+		if f.Message != "" {
+			fmt.Fprintf(&b, ": %s", f.Message)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,98 @@
+package junit
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected *Summary
+		wantErr  string
+	}{
+		{
+			name: "report with nested test suites",
+			path: "testdata/results.xml",
+			expected: &Summary{Total: 4, Failed: 2, Skipped: 1, Failures: []Failure{
+				{Name: "api.UsersTest.test_delete", Message: "expected 204, got 500"},
+				{Name: "api.UsersTest.test_update", Message: "connection refused"},
+			}},
+		},
+		{
+			name: "single test suite",
+			path: "testdata/nested/single_suite.xml",
+			expected: &Summary{Total: 2, Failed: 1, Failures: []Failure{
+				{Name: "cmd.TestSchedule", Message: "schedule_test.go:12: want 1, got 2"},
+			}},
+		},
+		{
+			name:     "directory",
+			path:     "testdata",
+			expected: &Summary{Total: 6, Failed: 3, Skipped: 1},
+		},
+		{
+			name:     "glob pattern",
+			path:     filepath.Join("testdata", "*", "*.xml"),
+			expected: &Summary{Total: 2, Failed: 1},
+		},
+		{
+			name:    "no reports",
+			path:    "testdata/missing",
+			wantErr: "no JUnit reports found",
+		},
+		{
+			name:    "invalid report",
+			path:    "testdata/invalid.txt",
+			wantErr: "unable to parse JUnit report",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Load(tt.path)
+			if tt.wantErr != "" {
+				assert.Check(t, cmp.ErrorContains(err, tt.wantErr))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, cmp.Equal(s.Total, tt.expected.Total))
+			assert.Check(t, cmp.Equal(s.Failed, tt.expected.Failed))
+			assert.Check(t, cmp.Equal(s.Skipped, tt.expected.Skipped))
+			if tt.expected.Failures != nil {
+				assert.Check(t, cmp.DeepEqual(s.Failures, tt.expected.Failures))
+			}
+		})
+	}
+}
+
+func TestEnvVars(t *testing.T) {
+	s := &Summary{Total: 10, Failed: 3, Skipped: 2, Failures: []Failure{
+		{Name: "a.TestOne", Message: "boom"},
+		{Name: "a.TestTwo"},
+		{Name: "a.TestThree", Message: "timeout"},
+	}}
+
+	assert.Check(t, cmp.DeepEqual(s.EnvVars(2), map[string]string{
+		"SLACK_ORB_TESTS_TOTAL":   "10",
+		"SLACK_ORB_TESTS_PASSED":  "5",
+		"SLACK_ORB_TESTS_FAILED":  "3",
+		"SLACK_ORB_TESTS_SKIPPED": "2",
+		"SLACK_ORB_TEST_FAILURES": "• `a.TestOne`: boom\n• `a.TestTwo`\n…and 1 more",
+	}))
+	assert.Check(t, cmp.Equal(s.EnvVars(5)["SLACK_ORB_TEST_FAILURES"],
+		"• `a.TestOne`: boom\n• `a.TestTwo`\n• `a.TestThree`: timeout"))
+	assert.Check(t, cmp.Equal((&Summary{Total: 1}).EnvVars(5)["SLACK_ORB_TEST_FAILURES"], "No failing tests"))
+}
+
+func TestFirstLine(t *testing.T) {
+	assert.Check(t, cmp.Equal(firstLine("\n  first\nsecond"), "first"))
+	long := firstLine(strings.Repeat("x", 200))
+	assert.Check(t, cmp.Equal(len([]rune(long)), maxMessageLength))
+	assert.Check(t, strings.HasSuffix(long, "…"))
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	// Embed the time zone database, so --timezone works on runners without one installed
+	_ "time/tzdata"
+
 	"github.com/CircleCI-Public/slack-orb-go/packages/cli/cmd"
 )
 
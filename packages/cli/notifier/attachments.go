@@ -0,0 +1,92 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/result"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/slack"
+)
+
+// attachment is a file matched by Config.Attachments. It is read once and shared in every channel.
+type attachment struct {
+	path    string
+	size    int64
+	content []byte
+	// err is set when the file cannot be attached, for example because it is too large.
+	err error
+}
+
+// loadAttachments expands the attachment patterns and reads the matching files.
+// Files larger than the size limit are kept with an error so that they show up in the result.
+func (n *Notifier) loadAttachments() ([]attachment, error) {
+	maxSize := n.cfg.MaxAttachmentSize
+	if maxSize <= 0 || maxSize > slack.MaxFileSize {
+		maxSize = slack.MaxFileSize
+	}
+
+	var attachments []attachment
+	seen := map[string]bool{}
+	for _, pattern := range n.cfg.Attachments {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, &ConfigError{Err: fmt.Errorf("invalid attachment pattern %q: %w", pattern, err)}
+		}
+		if len(matches) == 0 {
+			n.logger.Warnf("No files matched the attachment pattern %q", pattern)
+		}
+
+		for _, path := range matches {
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() || seen[path] {
+				continue
+			}
+			seen[path] = true
+
+			a := attachment{path: path, size: info.Size()}
+			if a.size > maxSize {
+				a.err = fmt.Errorf("the file is %d bytes, which exceeds the limit of %d bytes", a.size, maxSize)
+				n.logger.Warnf("Not attaching %s: %v", path, a.err)
+			} else if a.content, err = os.ReadFile(path); err != nil { //nolint:gosec // G304 the path is provided by the user
+				a.err = fmt.Errorf("unable to read the file: %w", err)
+				n.logger.Warnf("Not attaching %s: %v", path, a.err)
+			}
+			attachments = append(attachments, a)
+		}
+	}
+	return attachments, nil
+}
+
+// attach uploads the attachments in the thread of the message that was posted to the channel.
+// Upload failures are recorded in the channel result but do not fail the notification.
+func (n *Notifier) attach(ctx context.Context, channelResult *result.ChannelResult, attachments []attachment) {
+	threadTS := channelResult.TS
+	if channelResult.ThreadTS != "" {
+		threadTS = channelResult.ThreadTS
+	}
+	for _, a := range attachments {
+		fileResult := result.FileResult{Path: a.path, Size: a.size}
+		if a.err != nil {
+			fileResult.Error = a.err.Error()
+			channelResult.Files = append(channelResult.Files, fileResult)
+			continue
+		}
+
+		fileID, err := n.sender.UploadFile(ctx, slack.UploadFileOptions{
+			Filename:  filepath.Base(a.path),
+			Content:   a.content,
+			ChannelID: channelResult.ChannelID,
+			ThreadTS:  threadTS,
+		})
+		if err != nil {
+			fileResult.Error = err.Error()
+			n.logger.Warnf("Unable to attach %s in channel %s: %v", a.path, channelResult.Channel, err)
+		} else {
+			fileResult.FileID = fileID
+			n.logger.Infof("Attached %s in channel: %s", a.path, channelResult.Channel)
+		}
+		channelResult.Files = append(channelResult.Files, fileResult)
+	}
+}
@@ -0,0 +1,30 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/result"
+)
+
+// abandon records that the message was not sent to the channel, or that its sending was interrupted,
+// because the context is done, such as when the deadline of the notification was exceeded.
+func (n *Notifier) abandon(ctx context.Context, res *result.Result, channelResult result.ChannelResult) {
+	channelResult.Abandoned = true
+	channelResult.Error = ctx.Err().Error()
+	res.AddChannel(channelResult)
+	n.logger.Errorf("Abandoned sending the message to channel %s: %v", channelResult.Channel, ctx.Err())
+}
+
+// deadlineError returns the DeliveryError for the first abandoned channel, unless errors are ignored.
+// Every channel after it is abandoned as well.
+func (n *Notifier) deadlineError(ctx context.Context, res *result.Result) error {
+	if n.cfg.IgnoreErrors {
+		return nil
+	}
+	for _, channelResult := range res.Channels {
+		if channelResult.Abandoned {
+			return &DeliveryError{Channel: channelResult.Channel, Err: ctx.Err(), Abandoned: true}
+		}
+	}
+	return nil
+}
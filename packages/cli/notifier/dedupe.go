@@ -0,0 +1,171 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/dedupe"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/result"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/utils"
+)
+
+// ErrDuplicate is wrapped in the SkipError returned when a duplicate notification is suppressed.
+var ErrDuplicate = errors.New("the notification was already sent within the dedupe window")
+
+// dedupeEnabled reports whether notifications are deduplicated.
+func (n *Notifier) dedupeEnabled() bool {
+	return n.cfg.DedupeStore != nil && n.cfg.DedupeWindow > 0
+}
+
+// previous returns the entry of the notification sent for the dedupe key within the window, or nil.
+// A state that cannot be read is reported but does not prevent the notification from being sent.
+func (n *Notifier) previous() *dedupe.Entry {
+	if !n.dedupeEnabled() {
+		return nil
+	}
+	entry, err := n.cfg.DedupeStore.Get(n.cfg.DedupeKey)
+	if err != nil {
+		n.logger.Warnf("Unable to read the dedupe state, the notification is not deduplicated: %v", err)
+		return nil
+	}
+	if entry == nil || !entry.Within(n.cfg.DedupeWindow, time.Now()) {
+		return nil
+	}
+	return entry
+}
+
+// duplicate suppresses the notification, or replies to the previous one when the policy threads duplicates.
+func (n *Notifier) duplicate(
+	ctx context.Context, res *result.Result, payload string, attachments []attachment, entry *dedupe.Entry,
+) error {
+	entry.Count++
+	res.Occurrences = entry.Count
+	n.logger.Infof("The notification %q was already sent %d times since %s",
+		n.cfg.DedupeKey, entry.Count-1, entry.FirstSeen.Format(time.RFC3339))
+	defer n.store(*entry)
+
+	if n.cfg.DedupePolicy != dedupe.PolicyThread {
+		res.Skip(result.ReasonDuplicate)
+		return &SkipError{Reason: result.ReasonDuplicate, Err: ErrDuplicate}
+	}
+
+	for _, message := range entry.Messages {
+		if ctx.Err() != nil {
+			n.abandon(ctx, res, result.ChannelResult{Channel: message.Channel, Workspace: message.Workspace})
+			continue
+		}
+		w, err := n.inWorkspace(message.Workspace)
+		if err != nil {
+			res.AddChannel(result.ChannelResult{Channel: message.Channel, Workspace: message.Workspace, Error: err.Error()})
+			if err := n.deliveryFailed(message.Channel, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		channelResult, err := w.reply(ctx, payload, message)
+		if err != nil && ctx.Err() != nil {
+			n.abandon(ctx, res, channelResult)
+			continue
+		}
+		if err == nil {
+			w.attach(ctx, &channelResult, attachments)
+			w.updateCounter(ctx, payload, message, entry)
+		}
+		res.AddChannel(channelResult)
+		if err != nil {
+			if err := n.deliveryFailed(message.Channel, err); err != nil {
+				return err
+			}
+		} else {
+			n.logger.Infof("Successfully replied to the first notification in channel: %s", message.Channel)
+		}
+	}
+	return n.deadlineError(ctx, res)
+}
+
+// reply posts the message in the thread of the first notification.
+func (n *Notifier) reply(ctx context.Context, payload string, message dedupe.Message) (result.ChannelResult, error) {
+	threadPayload, err := utils.ApplyFunctionToJSON(payload, utils.AddRootProperty("thread_ts", message.TS))
+	if err != nil {
+		return result.ChannelResult{Channel: message.Channel, Error: err.Error()}, err
+	}
+	channelResult, err := n.post(ctx, threadPayload, message.ChannelID)
+	channelResult.Channel = message.Channel
+	channelResult.Workspace = message.Workspace
+	channelResult.ThreadTS = message.TS
+	return channelResult, err
+}
+
+// updateCounter adds the number of notifications sent since the first one to the first notification.
+// Failing to update it does not fail the notification.
+func (n *Notifier) updateCounter(ctx context.Context, payload string, message dedupe.Message, entry *dedupe.Entry) {
+	verb := "Sent"
+	if n.cfg.Status == "fail" {
+		verb = "Failed"
+	}
+	counter := fmt.Sprintf(":repeat: %s %d times since <!date^%d^{date_short_pretty} at {time}|%s>",
+		verb, entry.Count, entry.FirstSeen.Unix(), entry.FirstSeen.UTC().Format(time.RFC3339))
+
+	updated, err := utils.ApplyFunctionToJSON(payload, appendContext(counter))
+	if err == nil {
+		err = n.sender.UpdateMessage(ctx, updated, message.ChannelID, message.TS)
+	}
+	if err != nil {
+		n.logger.Warnf("Unable to update the counter of the first notification in channel %s: %v", message.Channel, err)
+	}
+}
+
+// remember stores the messages that were posted, so that later notifications can be deduplicated.
+func (n *Notifier) remember(res *result.Result) {
+	if !n.dedupeEnabled() {
+		return
+	}
+	entry := dedupe.Entry{FirstSeen: time.Now(), Count: 1}
+	for _, channel := range res.Channels {
+		if channel.TS != "" {
+			entry.Messages = append(entry.Messages, dedupe.Message{
+				Channel:   channel.Channel,
+				ChannelID: channel.ChannelID,
+				TS:        channel.TS,
+				Workspace: channel.Workspace,
+			})
+		}
+	}
+	if len(entry.Messages) == 0 {
+		return
+	}
+	res.Occurrences = entry.Count
+	n.store(entry)
+}
+
+func (n *Notifier) store(entry dedupe.Entry) {
+	if err := n.cfg.DedupeStore.Put(n.cfg.DedupeKey, entry); err != nil {
+		n.logger.Warnf("Unable to write the dedupe state: %v", err)
+	}
+}
+
+// appendContext adds a context block with text at the end of the message,
+// or a line to its text when the message has no blocks.
+func appendContext(text string) func(interface{}) interface{} {
+	return func(data interface{}) interface{} {
+		message, ok := data.(map[string]interface{})
+		if !ok {
+			return data
+		}
+		blocks, ok := message["blocks"].([]interface{})
+		if !ok {
+			existing, _ := message["text"].(string)
+			message["text"] = strings.TrimSpace(existing + "\n" + text)
+			return message
+		}
+		message["blocks"] = append(blocks, map[string]interface{}{
+			"type":     "context",
+			"elements": []interface{}{map[string]interface{}{"type": "mrkdwn", "text": text}},
+		})
+		return message
+	}
+}
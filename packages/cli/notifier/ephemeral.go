@@ -0,0 +1,63 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/result"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/slack"
+)
+
+// ErrUnknownUser is wrapped in the SkipError returned when an ephemeral message is meant for a user
+// whose Slack ID and email address are both unknown.
+var ErrUnknownUser = errors.New("the Slack user the ephemeral message is meant for is unknown")
+
+// directMessageFallbacks are the errors of chat.postEphemeral for which the message
+// is sent to the user as a direct message instead.
+var directMessageFallbacks = []string{"user_not_in_channel", "not_in_channel", "channel_not_found", "is_archived"}
+
+// postEphemeral shows the message to the ephemeral user in the channel, or sends it to them
+// as a direct message when it cannot be shown in the channel.
+func (n *Notifier) postEphemeral(ctx context.Context, payload, channel string) (result.ChannelResult, error) {
+	user := n.cfg.EphemeralUser
+	channelResult := result.ChannelResult{Channel: channel, User: user, Ephemeral: true}
+
+	_, err := n.sender.PostEphemeral(ctx, payload, channel, user)
+	if err == nil {
+		return channelResult, nil
+	}
+	if !fallsBackToDirectMessage(err) {
+		channelResult.Error = err.Error()
+		return channelResult, err
+	}
+
+	n.logger.Warnf("Unable to show the message to %s in channel %s (%v), sending it as a direct message", user, channel, err)
+	dmResult, err := n.sendDirect(ctx, payload, channel, user, time.Time{})
+	dmResult.Fallback = result.FallbackDirectMessage
+	return dmResult, err
+}
+
+// ephemeralFallback returns result.FallbackDirectMessage when the message is meant for a single user whose
+// Slack ID is unknown and is sent to their email address instead, or the empty string.
+func (n *Notifier) ephemeralFallback() string {
+	if n.cfg.Ephemeral && n.cfg.EphemeralUser == "" && n.cfg.EphemeralEmail != "" {
+		return result.FallbackDirectMessage
+	}
+	return ""
+}
+
+// unknownUser reports whether the message is meant for a single user who cannot be found in Slack.
+// The message is never posted in the channels instead, since everyone would see it.
+func (n *Notifier) unknownUser() bool {
+	return n.cfg.Ephemeral && n.cfg.EphemeralUser == "" && n.cfg.EphemeralEmail == ""
+}
+
+func fallsBackToDirectMessage(err error) bool {
+	for _, code := range directMessageFallbacks {
+		if slack.IsAPIError(err, code) {
+			return true
+		}
+	}
+	return false
+}
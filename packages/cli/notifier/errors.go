@@ -0,0 +1,63 @@
+package notifier
+
+import "fmt"
+
+// ConfigError is returned when the Config is invalid.
+type ConfigError struct {
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("invalid configuration: %v", e.Err)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// SkipError is returned when the notification is not sent because the job
+// status or the post conditions do not match, or because it is a duplicate. Err is one of
+// slack.ErrStatusMismatch, slack.ErrPostConditionNotMet, slack.ErrNoMatchingPaths,
+// slack.ErrSkippedByCommit, slack.ErrOutsideDeliveryWindow or ErrDuplicate.
+type SkipError struct {
+	Reason string
+	Err    error
+}
+
+func (e *SkipError) Error() string {
+	return fmt.Sprintf("notification skipped: %v", e.Err)
+}
+
+func (e *SkipError) Unwrap() error {
+	return e.Err
+}
+
+// RenderError is returned when the message template cannot be found or rendered.
+type RenderError struct {
+	Err error
+}
+
+func (e *RenderError) Error() string {
+	return fmt.Sprintf("failed to build message body: %v", e.Err)
+}
+
+func (e *RenderError) Unwrap() error {
+	return e.Err
+}
+
+// DeliveryError is returned when the message could not be delivered to a channel.
+type DeliveryError struct {
+	Channel string
+	Err     error
+	// Abandoned is set when the context was done before the message was sent to the channel,
+	// such as when the deadline of the notification was exceeded.
+	Abandoned bool
+}
+
+func (e *DeliveryError) Error() string {
+	return fmt.Sprintf("error posting to channel %s: %v", e.Channel, e.Err)
+}
+
+func (e *DeliveryError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,503 @@
+// Package notifier sends a Slack notification for a CI job.
+//
+// It is the library behind the notify command and can be embedded in other
+// tools. Templates are expanded against the process environment.
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/dedupe"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/result"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/slack"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/utils"
+)
+
+// Config describes a notification and where to send it.
+type Config struct {
+	// Channels are channels or slack.Recipient entries, such as "user:alice@example.com" or "group:oncall",
+	// which are sent a direct message instead. Entries prefixed by the name of a workspace, as in
+	// "status:incidents", are sent with the Sender of that workspace in Options.Workspaces.
+	Channels []string
+
+	// Trigger matching
+	Status        string
+	Event         string
+	Branch        string
+	Tag           string
+	BranchPattern string
+	TagPattern    string
+	InvertMatch   bool
+
+	// Message template
+	TemplateVar    string
+	TemplatePath   string
+	TemplateInline string
+	TemplateName   string
+
+	// IgnoreErrors keeps posting to the remaining channels when a channel fails,
+	// and does not return a DeliveryError.
+	IgnoreErrors bool
+	// Permalinks looks up the permalink of every posted message. This costs
+	// an extra API call per channel.
+	Permalinks bool
+	// PostAt schedules the message to be posted at the given time instead of
+	// posting it right away. The zero value posts right away.
+	PostAt time.Time
+
+	// DeliveryWindow restricts when notifications are delivered. It is ignored when nil.
+	// DeliveryWindowPolicy is one of the slack.DeliveryWindowPolicy values and decides
+	// what happens to notifications outside the window. It defaults to skipping them.
+	DeliveryWindow       *slack.DeliveryWindow
+	DeliveryWindowPolicy string
+
+	// Attachments are paths or glob patterns of files uploaded in the thread of the
+	// posted message. Files larger than MaxAttachmentSize bytes are not uploaded.
+	// A zero MaxAttachmentSize only applies Slack's own limit.
+	Attachments       []string
+	MaxAttachmentSize int64
+
+	// CommitMessage is checked for the "[skip slack]" and "[slack:#channel]" directives.
+	// CommitChannelMode is one of the slack.CommitChannelMode values and decides whether the
+	// channels requested by the commit are added to Channels or replace them. It defaults to adding them.
+	CommitMessage     string
+	CommitChannelMode string
+
+	// PathPatterns are glob patterns, such as "services/api/**", matched against ChangedFiles.
+	// When set, the notification is only sent if a changed file matches one of them.
+	PathPatterns []string
+	ChangedFiles []string
+
+	// DryRun renders the message and evaluates the conditions without sending anything.
+	DryRun bool
+
+	// DedupeStore remembers the notifications sent for DedupeKey, such as "project/branch/job/status".
+	// When it is set and DedupeWindow is not zero, a notification repeated within the window is handled
+	// according to DedupePolicy, one of the dedupe.Policy values. It defaults to suppressing it.
+	DedupeStore  dedupe.Store
+	DedupeKey    string
+	DedupeWindow time.Duration
+	DedupePolicy string
+
+	// EphemeralUser is the ID of the Slack user, such as "U0123456789", the message is shown to in every
+	// channel with chat.postEphemeral, so that nobody else sees it. When the message cannot be shown in a
+	// channel, such as when the user is not a member, it is sent to the user as a direct message instead.
+	EphemeralUser string
+	// Ephemeral is set when the message is meant for a single user whose ID may be unknown, such as a
+	// user of the CI provider that is not mapped to a Slack user. Without an EphemeralUser, the message is
+	// sent as a direct message to the user with the EphemeralEmail address, such as the author of the
+	// commit, or skipped when there is none.
+	Ephemeral      bool
+	EphemeralEmail string
+}
+
+// Validate checks whether the Config can be used to send a notification.
+func (c *Config) Validate() error {
+	if len(c.Channels) == 0 {
+		return &ConfigError{Err: errors.New("no channel was provided")}
+	}
+	for _, channel := range c.Channels {
+		if _, err := slack.ParseRecipient(channel); err != nil {
+			return &ConfigError{Err: err}
+		}
+	}
+	if c.Status != "pass" && c.Status != "fail" {
+		return &ConfigError{Err: errors.New("the status must be one of \"pass\" or \"fail\"")}
+	}
+	if !c.PostAt.IsZero() && !c.PostAt.After(time.Now()) {
+		return &ConfigError{Err: errors.New("the post time must be in the future")}
+	}
+	if (c.Ephemeral || c.EphemeralUser != "") && !c.PostAt.IsZero() {
+		return &ConfigError{Err: errors.New("ephemeral messages cannot be scheduled")}
+	}
+	if (c.Ephemeral || c.EphemeralUser != "") && c.DeliveryWindow != nil &&
+		c.DeliveryWindowPolicy == slack.DeliveryWindowPolicyDefer {
+		return &ConfigError{Err: errors.New("ephemeral messages cannot be deferred to the delivery window")}
+	}
+	switch c.DeliveryWindowPolicy {
+	case "", slack.DeliveryWindowPolicySkip, slack.DeliveryWindowPolicyDefer, slack.DeliveryWindowPolicyFailuresOnly:
+	default:
+		return &ConfigError{Err: fmt.Errorf("unknown delivery window policy %q", c.DeliveryWindowPolicy)}
+	}
+	switch c.CommitChannelMode {
+	case "", slack.CommitChannelModeAppend, slack.CommitChannelModeReplace:
+	default:
+		return &ConfigError{Err: fmt.Errorf("unknown commit channel mode %q", c.CommitChannelMode)}
+	}
+	switch c.DedupePolicy {
+	case "", dedupe.PolicySuppress, dedupe.PolicyThread:
+	default:
+		return &ConfigError{Err: fmt.Errorf("unknown dedupe policy %q", c.DedupePolicy)}
+	}
+	if c.DedupeStore != nil && c.DedupeWindow > 0 && c.DedupeKey == "" {
+		return &ConfigError{Err: errors.New("the dedupe key must not be empty")}
+	}
+	for _, pattern := range c.PathPatterns {
+		if _, err := utils.CompileGlob(pattern); err != nil {
+			return &ConfigError{Err: err}
+		}
+	}
+	return nil
+}
+
+// Sender delivers messages to Slack. It is implemented by *slack.Client.
+type Sender interface {
+	PostMessage(ctx context.Context, message, channel string) (*slack.PostMessageResponse, error)
+	ScheduleMessage(ctx context.Context, message, channel string, postAt time.Time) (*slack.ScheduleMessageResponse, error)
+	GetPermalink(ctx context.Context, channel, ts string) (string, error)
+	UpdateMessage(ctx context.Context, message, channel, ts string) error
+	UploadFile(ctx context.Context, options slack.UploadFileOptions) (string, error)
+	PostEphemeral(ctx context.Context, message, channel, user string) (string, error)
+	OpenConversation(ctx context.Context, users ...string) (string, error)
+	LookupUserByEmail(ctx context.Context, email string) (string, error)
+	UserGroupMembers(ctx context.Context, group string) ([]string, error)
+}
+
+type Options struct {
+	Sender Sender
+	// Workspaces are the senders of the named workspaces, by name. The Sender is used for
+	// the channels without a workspace.
+	Workspaces map[string]Sender
+	// Logger receives progress messages. They are discarded when it is nil.
+	Logger *log.Logger
+}
+
+type Notifier struct {
+	cfg        Config
+	sender     Sender
+	workspaces map[string]Sender
+	logger     *log.Logger
+}
+
+func New(cfg Config, options Options) *Notifier {
+	logger := options.Logger
+	if logger == nil {
+		logger = log.New(io.Discard)
+	}
+
+	return &Notifier{
+		cfg:        cfg,
+		sender:     options.Sender,
+		workspaces: options.Workspaces,
+		logger:     logger,
+	}
+}
+
+// Notify renders the message and posts it to every channel, unless the Config is a dry run.
+// The returned result is never nil and describes what happened, even when an error is returned.
+// The error is one of *ConfigError, *SkipError, *RenderError or *DeliveryError.
+func (n *Notifier) Notify(ctx context.Context) (*result.Result, error) {
+	res := result.New()
+
+	if err := n.cfg.Validate(); err != nil {
+		res.Fail(result.ReasonConfigError, err)
+		return res, err
+	}
+	if err := n.validateWorkspaces(); err != nil {
+		res.Fail(result.ReasonConfigError, err)
+		return res, err
+	}
+
+	notification := n.notification()
+	payload, err := notification.BuildMessageBody()
+	if err != nil {
+		for skipErr, reason := range skipReasons {
+			if errors.Is(err, skipErr) {
+				res.Skip(reason)
+				return res, &SkipError{Reason: reason, Err: err}
+			}
+		}
+
+		res.Fail(result.ReasonRenderError, err)
+		return res, &RenderError{Err: err}
+	}
+	res.PayloadHash = result.HashPayload(payload)
+	if len(n.cfg.PathPatterns) > 0 {
+		res.MatchedPaths = notification.MatchingPaths()
+		n.logger.Infof("Changed files matching the path patterns: %v", res.MatchedPaths)
+	}
+	if n.unknownUser() {
+		res.Skip(result.ReasonUnknownUser)
+		return res, &SkipError{Reason: result.ReasonUnknownUser, Err: ErrUnknownUser}
+	}
+	postAt := n.sendAt()
+
+	n.logger.Debugf("Posting the following JSON to Slack:\n")
+	colorizedJSON, err := utils.ColorizeJSON(payload)
+	if err != nil {
+		res.Fail(result.ReasonRenderError, err)
+		return res, &RenderError{Err: err}
+	}
+	n.logger.Debug(colorizedJSON)
+
+	attachments, err := n.loadAttachments()
+	if err != nil {
+		res.Fail(result.ReasonConfigError, err)
+		return res, err
+	}
+	if len(attachments) > 0 && !postAt.IsZero() {
+		n.logger.Warnf("Attachments are not uploaded for scheduled messages")
+		attachments = nil
+	}
+	if len(attachments) > 0 && n.cfg.EphemeralUser != "" {
+		n.logger.Warnf("Attachments are not uploaded for ephemeral messages")
+		attachments = nil
+	}
+
+	// Scheduled messages are never deduplicated, since they cannot be threaded before they are posted.
+	var previous *dedupe.Entry
+	if postAt.IsZero() {
+		previous = n.previous()
+	}
+	if n.cfg.DryRun {
+		n.dryRun(res, previous, postAt)
+		return res, nil
+	}
+	if previous != nil {
+		return res, n.duplicate(ctx, res, payload, attachments, previous)
+	}
+	err = n.deliver(ctx, res, payload, attachments, postAt)
+	n.remember(res)
+	return res, err
+}
+
+// deliver posts the message in every channel, or sends it to every recipient, and records the outcome in res.
+// The message is scheduled when postAt is not zero.
+func (n *Notifier) deliver(
+	ctx context.Context, res *result.Result, payload string, attachments []attachment, postAt time.Time,
+) error {
+	for _, channel := range n.channels() {
+		if ctx.Err() != nil {
+			n.abandon(ctx, res, result.ChannelResult{Channel: channel})
+			continue
+		}
+		w, destinations, err := n.resolve(ctx, channel)
+		if err != nil && ctx.Err() != nil {
+			n.abandon(ctx, res, result.ChannelResult{Channel: channel})
+			continue
+		}
+		if err != nil {
+			res.AddChannel(result.ChannelResult{Channel: channel, Error: err.Error()})
+			if err := n.deliveryFailed(channel, err); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, d := range destinations {
+			if err := w.deliverTo(ctx, res, payload, attachments, d, postAt); err != nil {
+				return err
+			}
+		}
+	}
+
+	return n.deadlineError(ctx, res)
+}
+
+// deliverTo sends the message to the destination and records the outcome in res.
+// It only returns an error when the delivery must stop.
+func (n *Notifier) deliverTo(
+	ctx context.Context, res *result.Result, payload string, attachments []attachment, d destination, postAt time.Time,
+) error {
+	if ctx.Err() != nil {
+		n.abandon(ctx, res, result.ChannelResult{Channel: d.channel, Workspace: d.recipient.Workspace, User: d.user})
+		return nil
+	}
+
+	var (
+		channelResult result.ChannelResult
+		err           error
+	)
+	switch {
+	case d.user != "":
+		channelResult, err = n.sendDirect(ctx, payload, d.channel, d.user, postAt)
+	case n.cfg.EphemeralUser != "":
+		channelResult, err = n.postEphemeral(ctx, payload, d.recipient.Name)
+	case !postAt.IsZero():
+		channelResult, err = n.schedule(ctx, payload, d.recipient.Name, postAt)
+	default:
+		channelResult, err = n.post(ctx, payload, d.recipient.Name)
+	}
+	channelResult.Channel = d.channel
+	channelResult.Workspace = d.recipient.Workspace
+	if fallback := n.ephemeralFallback(); fallback != "" {
+		channelResult.Fallback = fallback
+	}
+	if err != nil && ctx.Err() != nil {
+		n.abandon(ctx, res, channelResult)
+		return nil
+	}
+	if err == nil && channelResult.TS != "" {
+		n.attach(ctx, &channelResult, attachments)
+	}
+	res.AddChannel(channelResult)
+	if err != nil {
+		return n.deliveryFailed(d.channel, err)
+	}
+	n.logDelivery(d.channel, channelResult)
+	return nil
+}
+
+// deliveryFailed returns the DeliveryError stopping the delivery, or logs err and returns nil
+// when errors are ignored.
+func (n *Notifier) deliveryFailed(channel string, err error) error {
+	if !n.cfg.IgnoreErrors {
+		return &DeliveryError{Channel: channel, Err: err}
+	}
+
+	n.logger.Errorf("Error: \n%v\n", err)
+	return nil
+}
+
+func (n *Notifier) logDelivery(channel string, channelResult result.ChannelResult) {
+	switch {
+	case channelResult.ScheduledMessageID != "":
+		n.logger.Infof("Successfully scheduled message to channel: %s at %s", channel, channelResult.PostAt)
+	case channelResult.Fallback == result.FallbackDirectMessage:
+		n.logger.Infof("Successfully sent the message for channel %s to %s as a direct message", channel, channelResult.User)
+	case channelResult.Ephemeral:
+		n.logger.Infof("Successfully posted ephemeral message for %s to channel: %s", channelResult.User, channel)
+	case channelResult.User != "":
+		n.logger.Infof("Successfully sent direct message to %s for recipient: %s", channelResult.User, channel)
+	default:
+		n.logger.Infof("Successfully posted message to channel: %s", channel)
+	}
+}
+
+// dryRun records the channels the message would be sent to, without calling Slack.
+// Duplicates are reported but not recorded.
+func (n *Notifier) dryRun(res *result.Result, previous *dedupe.Entry, postAt time.Time) {
+	res.Decision = result.DecisionDryRun
+	if previous != nil {
+		res.Occurrences = previous.Count + 1
+		if n.cfg.DedupePolicy == dedupe.PolicyThread {
+			n.logger.Infof("Dry run: the notification %q is a duplicate and would be threaded", n.cfg.DedupeKey)
+		} else {
+			n.logger.Infof("Dry run: the notification %q is a duplicate and would be suppressed", n.cfg.DedupeKey)
+		}
+	}
+	for _, channel := range n.channels() {
+		recipient, _ := slack.ParseRecipient(channel)
+		switch {
+		case recipient.Type == slack.RecipientUser || recipient.Type == slack.RecipientGroup:
+			n.logger.Infof("Dry run: the message would be sent as a direct message to recipient: %s", channel)
+		case n.cfg.EphemeralUser != "":
+			n.logger.Infof("Dry run: the message would be shown to %s in channel: %s", n.cfg.EphemeralUser, channel)
+		case postAt.IsZero():
+			n.logger.Infof("Dry run: the message would be posted to channel: %s", channel)
+		default:
+			n.logger.Infof("Dry run: the message would be scheduled to channel: %s at %s",
+				channel, postAt.Format(time.RFC3339))
+		}
+		res.AddChannel(result.ChannelResult{Channel: channel})
+	}
+}
+
+// skipReasons maps the errors returned when a notification is skipped to the reason in the result.
+var skipReasons = map[error]string{
+	slack.ErrStatusMismatch:        result.ReasonStatusMismatch,
+	slack.ErrPostConditionNotMet:   result.ReasonPostConditionNotMet,
+	slack.ErrNoMatchingPaths:       result.ReasonNoMatchingPaths,
+	slack.ErrSkippedByCommit:       result.ReasonSkippedByCommit,
+	slack.ErrOutsideDeliveryWindow: result.ReasonOutsideWindow,
+}
+
+// channels returns the channels to post to, including the ones requested by the commit message.
+func (n *Notifier) channels() []string {
+	directives := slack.ParseCommitDirectives(n.cfg.CommitMessage)
+	channels := directives.ApplyChannels(n.cfg.Channels, n.cfg.CommitChannelMode)
+	if len(directives.Channels) > 0 {
+		n.logger.Infof("The commit message requests the channels %v", directives.Channels)
+	}
+
+	if n.ephemeralFallback() == result.FallbackDirectMessage {
+		n.logger.Warnf("The Slack user the message is meant for is unknown, sending it to %s as a direct message", n.cfg.EphemeralEmail)
+		return []string{slack.RecipientUser + ":" + n.cfg.EphemeralEmail}
+	}
+	return channels
+}
+
+// sendAt returns the time the message is scheduled for, or the zero time when it is posted right away.
+// It is the PostAt of the Config, unless the send time is outside the delivery window and the policy
+// defers notifications, in which case it is the start of the next window.
+func (n *Notifier) sendAt() time.Time {
+	if n.cfg.DeliveryWindow == nil || n.cfg.DeliveryWindowPolicy != slack.DeliveryWindowPolicyDefer {
+		return n.cfg.PostAt
+	}
+	at := n.cfg.PostAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+	next := n.cfg.DeliveryWindow.Next(at)
+	if next.Equal(at) {
+		return n.cfg.PostAt
+	}
+	n.logger.Infof("Outside of the delivery window %q, deferring the message to %s",
+		n.cfg.DeliveryWindow, next.Format(time.RFC3339))
+	return next
+}
+
+func (n *Notifier) notification() *slack.Notification {
+	return &slack.Notification{
+		Status:         n.cfg.Status,
+		Branch:         n.cfg.Branch,
+		Tag:            n.cfg.Tag,
+		Event:          n.cfg.Event,
+		BranchPattern:  n.cfg.BranchPattern,
+		TagPattern:     n.cfg.TagPattern,
+		InvertMatch:    n.cfg.InvertMatch,
+		TemplateVar:    n.cfg.TemplateVar,
+		TemplatePath:   n.cfg.TemplatePath,
+		TemplateInline: n.cfg.TemplateInline,
+		TemplateName:   n.cfg.TemplateName,
+
+		DeliveryWindow:       n.cfg.DeliveryWindow,
+		DeliveryWindowPolicy: n.cfg.DeliveryWindowPolicy,
+		PostAt:               n.cfg.PostAt,
+		CommitMessage:        n.cfg.CommitMessage,
+		PathPatterns:         n.cfg.PathPatterns,
+		ChangedFiles:         n.cfg.ChangedFiles,
+	}
+}
+
+func (n *Notifier) post(ctx context.Context, payload, channel string) (result.ChannelResult, error) {
+	channelResult := result.ChannelResult{Channel: channel}
+
+	resp, err := n.sender.PostMessage(ctx, payload, channel)
+	if err != nil {
+		channelResult.Error = err.Error()
+		return channelResult, err
+	}
+	channelResult.ChannelID = resp.Channel
+	channelResult.TS = resp.TS
+
+	if n.cfg.Permalinks {
+		permalink, err := n.sender.GetPermalink(ctx, resp.Channel, resp.TS)
+		if err != nil {
+			n.logger.Warnf("Unable to look up the permalink for channel %s: %v", channel, err)
+		}
+		channelResult.Permalink = permalink
+	}
+
+	return channelResult, nil
+}
+
+func (n *Notifier) schedule(ctx context.Context, payload, channel string, postAt time.Time) (result.ChannelResult, error) {
+	channelResult := result.ChannelResult{Channel: channel}
+
+	resp, err := n.sender.ScheduleMessage(ctx, payload, channel, postAt)
+	if err != nil {
+		channelResult.Error = err.Error()
+		return channelResult, err
+	}
+	channelResult.ChannelID = resp.Channel
+	channelResult.ScheduledMessageID = resp.ScheduledMessageID
+	channelResult.PostAt = time.Unix(resp.PostAt, 0).In(postAt.Location()).Format(time.RFC3339)
+
+	return channelResult, nil
+}
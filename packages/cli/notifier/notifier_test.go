@@ -0,0 +1,898 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/circleci/ex/testing/testcontext"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/dedupe"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/result"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/slack"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/utils"
+)
+
+type fakeSender struct {
+	posted    []string
+	messages  []string
+	updated   []string
+	uploaded  []slack.UploadFileOptions
+	ephemeral []string
+	groups    map[string][]string
+	failOn    map[string]error
+	// slow channels block until the context is done.
+	slow map[string]bool
+}
+
+func (f *fakeSender) PostMessage(ctx context.Context, message, channel string) (*slack.PostMessageResponse, error) {
+	if f.slow[channel] {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if err := f.failOn[channel]; err != nil {
+		return nil, err
+	}
+	f.posted = append(f.posted, channel)
+	f.messages = append(f.messages, message)
+	return &slack.PostMessageResponse{Channel: "ID-" + channel, TS: "1700000000.000100"}, nil
+}
+
+func (f *fakeSender) ScheduleMessage(_ context.Context, _, channel string, postAt time.Time) (*slack.ScheduleMessageResponse, error) {
+	if err := f.failOn[channel]; err != nil {
+		return nil, err
+	}
+	f.posted = append(f.posted, channel)
+	return &slack.ScheduleMessageResponse{Channel: "ID-" + channel, ScheduledMessageID: "Q-" + channel, PostAt: postAt.Unix()}, nil
+}
+
+func (f *fakeSender) GetPermalink(_ context.Context, channel, ts string) (string, error) {
+	return "https://example.slack.com/archives/" + channel + "/p" + ts, nil
+}
+
+func (f *fakeSender) UpdateMessage(_ context.Context, message, _, _ string) error {
+	f.updated = append(f.updated, message)
+	return nil
+}
+
+func (f *fakeSender) UploadFile(_ context.Context, options slack.UploadFileOptions) (string, error) {
+	if err := f.failOn[options.Filename]; err != nil {
+		return "", err
+	}
+	f.uploaded = append(f.uploaded, options)
+	return "F-" + options.Filename, nil
+}
+
+func (f *fakeSender) PostEphemeral(_ context.Context, _, channel, user string) (string, error) {
+	if err := f.failOn[channel]; err != nil {
+		return "", err
+	}
+	f.ephemeral = append(f.ephemeral, channel+"/"+user)
+	return "1700000000.000200", nil
+}
+
+func (f *fakeSender) OpenConversation(_ context.Context, users ...string) (string, error) {
+	return "D-" + strings.Join(users, ","), nil
+}
+
+func (f *fakeSender) LookupUserByEmail(_ context.Context, email string) (string, error) {
+	if err := f.failOn[email]; err != nil {
+		return "", err
+	}
+	return "U-" + email, nil
+}
+
+func (f *fakeSender) UserGroupMembers(_ context.Context, group string) ([]string, error) {
+	if err := f.failOn[group]; err != nil {
+		return nil, err
+	}
+	return f.groups[group], nil
+}
+
+func validConfig() Config {
+	return Config{
+		Channels:       []string{"one", "two"},
+		Status:         "pass",
+		Event:          "always",
+		Branch:         "main",
+		BranchPattern:  ".+",
+		TagPattern:     ".+",
+		TemplateInline: `{"text": "hello"}`,
+	}
+}
+
+func TestNotify(t *testing.T) {
+	ctx := testcontext.Background()
+	errChannelNotFound := errors.New("channel_not_found")
+
+	tests := []struct {
+		name             string
+		modify           func(cfg *Config)
+		failOn           map[string]error
+		expectedErr      interface{}
+		expectedDecision result.Decision
+		expectedReason   string
+		expectedPosted   []string
+	}{
+		{
+			name:             "posts to every channel",
+			modify:           func(cfg *Config) {},
+			expectedDecision: result.DecisionPosted,
+			expectedPosted:   []string{"one", "two"},
+		},
+		{
+			name:             "invalid status",
+			modify:           func(cfg *Config) { cfg.Status = "unknown" },
+			expectedErr:      &ConfigError{},
+			expectedDecision: result.DecisionFailed,
+			expectedReason:   result.ReasonConfigError,
+		},
+		{
+			name:             "post time in the past",
+			modify:           func(cfg *Config) { cfg.PostAt = time.Now().Add(-time.Minute) },
+			expectedErr:      &ConfigError{},
+			expectedDecision: result.DecisionFailed,
+			expectedReason:   result.ReasonConfigError,
+		},
+		{
+			name:             "schedules to every channel",
+			modify:           func(cfg *Config) { cfg.PostAt = time.Now().Add(time.Hour) },
+			expectedDecision: result.DecisionScheduled,
+			expectedPosted:   []string{"one", "two"},
+		},
+		{
+			name:             "status mismatch",
+			modify:           func(cfg *Config) { cfg.Event = "fail" },
+			expectedErr:      &SkipError{},
+			expectedDecision: result.DecisionSkipped,
+			expectedReason:   result.ReasonStatusMismatch,
+		},
+		{
+			name:             "post condition not met",
+			modify:           func(cfg *Config) { cfg.BranchPattern = "release" },
+			expectedErr:      &SkipError{},
+			expectedDecision: result.DecisionSkipped,
+			expectedReason:   result.ReasonPostConditionNotMet,
+		},
+		{
+			name:             "unknown template",
+			modify:           func(cfg *Config) { cfg.TemplateInline = ""; cfg.TemplateName = "not_a_template" },
+			expectedErr:      &RenderError{},
+			expectedDecision: result.DecisionFailed,
+			expectedReason:   result.ReasonRenderError,
+		},
+		{
+			name:             "delivery error stops at the failing channel",
+			modify:           func(cfg *Config) {},
+			failOn:           map[string]error{"one": errChannelNotFound},
+			expectedErr:      &DeliveryError{},
+			expectedDecision: result.DecisionFailed,
+			expectedReason:   result.ReasonDeliveryError,
+		},
+		{
+			name:             "delivery error is ignored",
+			modify:           func(cfg *Config) { cfg.IgnoreErrors = true },
+			failOn:           map[string]error{"one": errChannelNotFound},
+			expectedDecision: result.DecisionFailed,
+			expectedReason:   result.ReasonDeliveryError,
+			expectedPosted:   []string{"two"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(&cfg)
+			sender := &fakeSender{failOn: tt.failOn}
+
+			res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+			if tt.expectedErr == nil {
+				assert.NilError(t, err)
+			} else {
+				assert.Check(t, cmp.ErrorType(err, tt.expectedErr))
+			}
+
+			assert.Assert(t, res != nil)
+			assert.Check(t, cmp.Equal(res.Decision, tt.expectedDecision))
+			assert.Check(t, cmp.Equal(res.Reason, tt.expectedReason))
+			assert.Check(t, cmp.DeepEqual(sender.posted, tt.expectedPosted))
+		})
+	}
+}
+
+// windowOutsideNow returns a delivery window that starts six hours from now.
+func windowOutsideNow(t *testing.T) *slack.DeliveryWindow {
+	return windowAt(t, time.Now().Add(6*time.Hour))
+}
+
+// windowAt returns a delivery window of an hour starting at start, every day.
+func windowAt(t *testing.T, start time.Time) *slack.DeliveryWindow {
+	start = start.UTC()
+	spec := start.Format("15:04") + "-" + start.Add(time.Hour).Format("15:04")
+	window, err := slack.ParseDeliveryWindow(spec, time.UTC)
+	assert.NilError(t, err)
+	return window
+}
+
+func TestNotifyDeliveryWindow(t *testing.T) {
+	ctx := testcontext.Background()
+
+	t.Run("skipped outside the window", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.DeliveryWindow = windowOutsideNow(t)
+		cfg.DeliveryWindowPolicy = slack.DeliveryWindowPolicySkip
+		sender := &fakeSender{}
+
+		res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+		assert.Check(t, cmp.ErrorIs(err, slack.ErrOutsideDeliveryWindow))
+		assert.Check(t, cmp.Equal(res.Decision, result.DecisionSkipped))
+		assert.Check(t, cmp.Equal(res.Reason, result.ReasonOutsideWindow))
+		assert.Check(t, cmp.Len(sender.posted, 0))
+	})
+
+	t.Run("failures are let through", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.Status = "fail"
+		cfg.DeliveryWindow = windowOutsideNow(t)
+		cfg.DeliveryWindowPolicy = slack.DeliveryWindowPolicyFailuresOnly
+
+		res, err := New(cfg, Options{Sender: &fakeSender{}}).Notify(ctx)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(res.Decision, result.DecisionPosted))
+	})
+
+	t.Run("deferred to the start of the window", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.DeliveryWindow = windowOutsideNow(t)
+		cfg.DeliveryWindowPolicy = slack.DeliveryWindowPolicyDefer
+
+		n := New(cfg, Options{Sender: &fakeSender{}})
+		res, err := n.Notify(ctx)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(res.Decision, result.DecisionScheduled))
+		postAt, err := time.Parse(time.RFC3339, res.Channels[0].PostAt)
+		assert.NilError(t, err)
+		assert.Check(t, cfg.DeliveryWindow.Contains(postAt))
+		assert.Check(t, n.cfg.PostAt.IsZero(), "the config of the notifier was changed")
+
+		res, err = n.Notify(ctx)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(res.Decision, result.DecisionScheduled))
+	})
+
+	t.Run("unknown policy", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.DeliveryWindow = windowOutsideNow(t)
+		cfg.DeliveryWindowPolicy = "sometimes"
+
+		_, err := New(cfg, Options{Sender: &fakeSender{}}).Notify(ctx)
+		assert.Check(t, cmp.ErrorType(err, &ConfigError{}))
+	})
+}
+
+func TestNotifyDeliveryWindowPostAt(t *testing.T) {
+	ctx := testcontext.Background()
+	now := time.Now()
+	// The window is outside of now, and contains inWindow but not outsideWindow.
+	inWindow := now.Add(6*time.Hour + 30*time.Minute)
+	outsideWindow := now.Add(2 * time.Hour)
+
+	tests := []struct {
+		name             string
+		policy           string
+		status           string
+		postAt           time.Time
+		windowAroundNow  bool
+		expectedDecision result.Decision
+		expectedPostAt   time.Time
+	}{
+		{
+			name:             "skip: scheduled inside the window",
+			policy:           slack.DeliveryWindowPolicySkip,
+			postAt:           inWindow,
+			expectedDecision: result.DecisionScheduled,
+			expectedPostAt:   inWindow,
+		},
+		{
+			name:             "skip: scheduled outside the window",
+			policy:           slack.DeliveryWindowPolicySkip,
+			postAt:           outsideWindow,
+			windowAroundNow:  true,
+			expectedDecision: result.DecisionSkipped,
+		},
+		{
+			name:             "failures-only: success scheduled inside the window",
+			policy:           slack.DeliveryWindowPolicyFailuresOnly,
+			postAt:           inWindow,
+			expectedDecision: result.DecisionScheduled,
+			expectedPostAt:   inWindow,
+		},
+		{
+			name:             "failures-only: success scheduled outside the window",
+			policy:           slack.DeliveryWindowPolicyFailuresOnly,
+			postAt:           outsideWindow,
+			windowAroundNow:  true,
+			expectedDecision: result.DecisionSkipped,
+		},
+		{
+			name:             "failures-only: failure scheduled outside the window",
+			policy:           slack.DeliveryWindowPolicyFailuresOnly,
+			status:           "fail",
+			postAt:           outsideWindow,
+			windowAroundNow:  true,
+			expectedDecision: result.DecisionScheduled,
+			expectedPostAt:   outsideWindow,
+		},
+		{
+			name:             "defer: scheduled inside the window",
+			policy:           slack.DeliveryWindowPolicyDefer,
+			postAt:           inWindow,
+			expectedDecision: result.DecisionScheduled,
+			expectedPostAt:   inWindow,
+		},
+		{
+			name:             "defer: scheduled outside the window",
+			policy:           slack.DeliveryWindowPolicyDefer,
+			postAt:           outsideWindow,
+			windowAroundNow:  true,
+			expectedDecision: result.DecisionScheduled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			if tt.status != "" {
+				cfg.Status = tt.status
+				cfg.Event = "always"
+			}
+			cfg.PostAt = tt.postAt
+			cfg.DeliveryWindow = windowOutsideNow(t)
+			if tt.windowAroundNow {
+				cfg.DeliveryWindow = windowAt(t, now.Add(-30*time.Minute))
+			}
+			cfg.DeliveryWindowPolicy = tt.policy
+
+			res, err := New(cfg, Options{Sender: &fakeSender{}}).Notify(ctx)
+			assert.Check(t, cmp.Equal(res.Decision, tt.expectedDecision))
+			if tt.expectedDecision == result.DecisionSkipped {
+				assert.Check(t, cmp.ErrorIs(err, slack.ErrOutsideDeliveryWindow))
+				return
+			}
+			assert.NilError(t, err)
+			postAt, err := time.Parse(time.RFC3339, res.Channels[0].PostAt)
+			assert.NilError(t, err)
+			if tt.expectedPostAt.IsZero() {
+				assert.Check(t, cfg.DeliveryWindow.Contains(postAt), "the message was not deferred to the window")
+				return
+			}
+			assert.Check(t, cmp.Equal(postAt.Unix(), tt.expectedPostAt.Unix()))
+		})
+	}
+}
+
+func TestNotifyPermalinks(t *testing.T) {
+	ctx := testcontext.Background()
+	cfg := validConfig()
+	cfg.Channels = []string{"one"}
+	cfg.Permalinks = true
+
+	res, err := New(cfg, Options{Sender: &fakeSender{}}).Notify(ctx)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.DeepEqual(res.Channels, []result.ChannelResult{{
+		Channel:   "one",
+		ChannelID: "ID-one",
+		TS:        "1700000000.000100",
+		Permalink: "https://example.slack.com/archives/ID-one/p1700000000.000100",
+	}}))
+	assert.Check(t, cmp.Equal(res.PayloadHash, result.HashPayload(`{"text":"hello"}`)))
+
+	var skipErr *SkipError
+	cfg.Event = "fail"
+	_, err = New(cfg, Options{Sender: &fakeSender{}}).Notify(ctx)
+	assert.Check(t, errors.As(err, &skipErr))
+	assert.Check(t, errors.Is(err, slack.ErrStatusMismatch))
+}
+
+func TestNotifyAttachments(t *testing.T) {
+	ctx := testcontext.Background()
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "a.log"), []byte("log a"), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "b.log"), []byte("log b"), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "large.xml"), []byte("<testsuites></testsuites>"), 0o600))
+
+	t.Run("uploads the matching files in the thread of every message", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.Attachments = []string{filepath.Join(dir, "*.log"), filepath.Join(dir, "a.log"), filepath.Join(dir, "*.txt")}
+		sender := &fakeSender{}
+
+		res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Len(sender.uploaded, 4))
+		assert.Check(t, cmp.DeepEqual(sender.uploaded[0], slack.UploadFileOptions{
+			Filename:  "a.log",
+			Content:   []byte("log a"),
+			ChannelID: "ID-one",
+			ThreadTS:  "1700000000.000100",
+		}))
+		assert.Check(t, cmp.DeepEqual(res.Channels[1].Files, []result.FileResult{
+			{Path: filepath.Join(dir, "a.log"), FileID: "F-a.log", Size: 5},
+			{Path: filepath.Join(dir, "b.log"), FileID: "F-b.log", Size: 5},
+		}))
+	})
+
+	t.Run("files over the size limit are not uploaded", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.Channels = []string{"one"}
+		cfg.Attachments = []string{filepath.Join(dir, "*")}
+		cfg.MaxAttachmentSize = 10
+		sender := &fakeSender{}
+
+		res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(res.Decision, result.DecisionPosted))
+		assert.Check(t, cmp.Len(sender.uploaded, 2))
+		assert.Check(t, cmp.Contains(res.Channels[0].Files[2].Error, "exceeds the limit of 10 bytes"))
+	})
+
+	t.Run("upload errors do not fail the notification", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.Channels = []string{"one"}
+		cfg.Attachments = []string{filepath.Join(dir, "a.log")}
+		sender := &fakeSender{failOn: map[string]error{"a.log": errors.New("channel_not_found")}}
+
+		res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(res.Decision, result.DecisionPosted))
+		assert.Check(t, cmp.Equal(res.Channels[0].Files[0].Error, "channel_not_found"))
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.Attachments = []string{"[invalid"}
+		sender := &fakeSender{}
+
+		_, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+		assert.Check(t, cmp.ErrorType(err, &ConfigError{}))
+		assert.Check(t, cmp.Len(sender.posted, 0))
+	})
+}
+
+func TestNotifyCommitDirectives(t *testing.T) {
+	ctx := testcontext.Background()
+
+	tests := []struct {
+		name             string
+		message          string
+		mode             string
+		expectedErr      error
+		expectedDecision result.Decision
+		expectedPosted   []string
+	}{
+		{
+			name:             "no directive",
+			message:          "Fix the build",
+			expectedDecision: result.DecisionPosted,
+			expectedPosted:   []string{"one", "two"},
+		},
+		{
+			name:             "skip",
+			message:          "Update the README [skip slack]",
+			expectedErr:      slack.ErrSkippedByCommit,
+			expectedDecision: result.DecisionSkipped,
+		},
+		{
+			name:             "channels are appended by default",
+			message:          "Release the API [slack:#api-team]",
+			expectedDecision: result.DecisionPosted,
+			expectedPosted:   []string{"one", "two", "api-team"},
+		},
+		{
+			name:             "channels replace the configured channels",
+			message:          "Release the API [slack:#api-team]",
+			mode:             slack.CommitChannelModeReplace,
+			expectedDecision: result.DecisionPosted,
+			expectedPosted:   []string{"api-team"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.CommitMessage = tt.message
+			cfg.CommitChannelMode = tt.mode
+			sender := &fakeSender{}
+
+			res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+			if tt.expectedErr == nil {
+				assert.NilError(t, err)
+			} else {
+				assert.Check(t, cmp.ErrorIs(err, tt.expectedErr))
+			}
+			assert.Check(t, cmp.Equal(res.Decision, tt.expectedDecision))
+			assert.Check(t, cmp.DeepEqual(sender.posted, tt.expectedPosted))
+		})
+	}
+
+	t.Run("unknown mode", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.CommitChannelMode = "merge"
+
+		_, err := New(cfg, Options{Sender: &fakeSender{}}).Notify(ctx)
+		assert.Check(t, cmp.ErrorType(err, &ConfigError{}))
+	})
+}
+
+func TestNotifyPathPatterns(t *testing.T) {
+	ctx := testcontext.Background()
+	changed := []string{"services/api/main.go", "services/api/go.mod", "README.md"}
+
+	t.Run("reports the matched paths", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.PathPatterns = []string{"services/api/**"}
+		cfg.ChangedFiles = changed
+		sender := &fakeSender{}
+
+		res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.DeepEqual(res.MatchedPaths, []string{"services/api/main.go", "services/api/go.mod"}))
+		assert.Check(t, cmp.Len(sender.posted, 2))
+	})
+
+	t.Run("skipped when no path matches", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.PathPatterns = []string{"services/web/**"}
+		cfg.ChangedFiles = changed
+		sender := &fakeSender{}
+
+		res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+		assert.Check(t, cmp.ErrorIs(err, slack.ErrNoMatchingPaths))
+		assert.Check(t, cmp.Equal(res.Reason, result.ReasonNoMatchingPaths))
+		assert.Check(t, cmp.Len(sender.posted, 0))
+	})
+}
+
+func TestNotifyDryRun(t *testing.T) {
+	ctx := testcontext.Background()
+	cfg := validConfig()
+	cfg.DryRun = true
+	sender := &fakeSender{}
+
+	res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Equal(res.Decision, result.DecisionDryRun))
+	assert.Check(t, cmp.DeepEqual(res.Channels, []result.ChannelResult{{Channel: "one"}, {Channel: "two"}}))
+	assert.Check(t, cmp.Len(sender.posted, 0))
+}
+
+type memoryStore map[string]dedupe.Entry
+
+func (m memoryStore) Get(key string) (*dedupe.Entry, error) {
+	entry, ok := m[key]
+	if !ok {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+func (m memoryStore) Put(key string, entry dedupe.Entry) error {
+	m[key] = entry
+	return nil
+}
+
+func TestNotifyDedupe(t *testing.T) {
+	ctx := testcontext.Background()
+	store := memoryStore{}
+	cfg := validConfig()
+	cfg.Status = "fail"
+	cfg.DedupeStore = store
+	cfg.DedupeKey = "repo/main/test/fail"
+	cfg.DedupeWindow = time.Hour
+
+	t.Run("first notification", func(t *testing.T) {
+		sender := &fakeSender{}
+		res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(res.Decision, result.DecisionPosted))
+		assert.Check(t, cmp.Equal(res.Occurrences, 1))
+		assert.Check(t, cmp.DeepEqual(store[cfg.DedupeKey].Messages, []dedupe.Message{
+			{Channel: "one", ChannelID: "ID-one", TS: "1700000000.000100"},
+			{Channel: "two", ChannelID: "ID-two", TS: "1700000000.000100"},
+		}))
+	})
+
+	t.Run("duplicate is suppressed", func(t *testing.T) {
+		sender := &fakeSender{}
+		res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+		assert.Check(t, errors.Is(err, ErrDuplicate))
+		assert.Check(t, cmp.Equal(res.Decision, result.DecisionSkipped))
+		assert.Check(t, cmp.Equal(res.Reason, result.ReasonDuplicate))
+		assert.Check(t, cmp.Equal(res.Occurrences, 2))
+		assert.Check(t, cmp.Len(sender.posted, 0))
+		assert.Check(t, cmp.Equal(store[cfg.DedupeKey].Count, 2))
+	})
+
+	t.Run("duplicate is threaded", func(t *testing.T) {
+		cfg := cfg
+		cfg.DedupePolicy = dedupe.PolicyThread
+		sender := &fakeSender{}
+		res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(res.Decision, result.DecisionPosted))
+		assert.Check(t, cmp.Equal(res.Occurrences, 3))
+		assert.Check(t, cmp.DeepEqual(sender.posted, []string{"ID-one", "ID-two"}))
+		assert.Check(t, cmp.Equal(sender.messages[0], `{"text":"hello","thread_ts":"1700000000.000100"}`))
+		assert.Check(t, cmp.Equal(res.Channels[0].ThreadTS, "1700000000.000100"))
+		assert.Assert(t, cmp.Len(sender.updated, 2))
+		assert.Check(t, cmp.Contains(sender.updated[0], ":repeat: Failed 3 times since"))
+	})
+
+	t.Run("dry run reports duplicates", func(t *testing.T) {
+		cfg := cfg
+		cfg.DryRun = true
+		res, err := New(cfg, Options{Sender: &fakeSender{}}).Notify(ctx)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(res.Occurrences, 4))
+		assert.Check(t, cmp.Equal(store[cfg.DedupeKey].Count, 3))
+	})
+
+	t.Run("window expired", func(t *testing.T) {
+		entry := store[cfg.DedupeKey]
+		entry.FirstSeen = time.Now().Add(-2 * time.Hour)
+		store[cfg.DedupeKey] = entry
+
+		sender := &fakeSender{}
+		res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(res.Occurrences, 1))
+		assert.Check(t, cmp.DeepEqual(sender.posted, []string{"one", "two"}))
+		assert.Check(t, cmp.Equal(store[cfg.DedupeKey].Count, 1))
+	})
+}
+
+func TestNotifyEphemeral(t *testing.T) {
+	ctx := testcontext.Background()
+	cfg := validConfig()
+	cfg.EphemeralUser = "U123"
+	attachment := filepath.Join(t.TempDir(), "build.log")
+	assert.NilError(t, os.WriteFile(attachment, []byte("log"), 0o600))
+	cfg.Attachments = []string{attachment}
+	sender := &fakeSender{failOn: map[string]error{"two": &slack.APIError{Code: "user_not_in_channel"}}}
+
+	res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Equal(res.Decision, result.DecisionPosted))
+	assert.Check(t, cmp.DeepEqual(sender.ephemeral, []string{"one/U123"}))
+	assert.Check(t, cmp.DeepEqual(sender.posted, []string{"D-U123"}))
+	assert.Check(t, cmp.Len(sender.uploaded, 0))
+	assert.Check(t, cmp.DeepEqual(res.Channels, []result.ChannelResult{
+		{Channel: "one", User: "U123", Ephemeral: true},
+		{Channel: "two", ChannelID: "ID-D-U123", TS: "1700000000.000100", User: "U123", Fallback: result.FallbackDirectMessage},
+	}))
+
+	t.Run("other errors fail", func(t *testing.T) {
+		sender := &fakeSender{failOn: map[string]error{"two": &slack.APIError{Code: "invalid_blocks"}}}
+		res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+		assert.Check(t, cmp.ErrorType(err, &DeliveryError{}))
+		assert.Check(t, cmp.Equal(res.Channels[1].Error, "invalid_blocks"))
+		assert.Check(t, cmp.Len(sender.posted, 0))
+	})
+
+	t.Run("cannot be scheduled", func(t *testing.T) {
+		cfg := cfg
+		cfg.PostAt = time.Now().Add(time.Hour)
+		_, err := New(cfg, Options{Sender: &fakeSender{}}).Notify(ctx)
+		assert.Check(t, cmp.ErrorContains(err, "ephemeral messages cannot be scheduled"))
+	})
+
+	t.Run("unknown user sent a direct message by email", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.Ephemeral = true
+		cfg.EphemeralEmail = "alice@example.com"
+		sender := &fakeSender{}
+
+		res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Len(sender.ephemeral, 0))
+		assert.Check(t, cmp.DeepEqual(sender.posted, []string{"D-U-alice@example.com"}))
+		assert.Check(t, cmp.DeepEqual(res.Channels, []result.ChannelResult{{
+			Channel:   "user:alice@example.com",
+			ChannelID: "ID-D-U-alice@example.com",
+			TS:        "1700000000.000100",
+			User:      "U-alice@example.com",
+			Fallback:  result.FallbackDirectMessage,
+		}}))
+	})
+
+	t.Run("unknown user without email skipped", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.Ephemeral = true
+		sender := &fakeSender{}
+
+		res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+		assert.Check(t, cmp.ErrorIs(err, ErrUnknownUser))
+		assert.Check(t, cmp.ErrorType(err, &SkipError{}))
+		assert.Check(t, cmp.Equal(res.Decision, result.DecisionSkipped))
+		assert.Check(t, cmp.Equal(res.Reason, result.ReasonUnknownUser))
+		assert.Check(t, cmp.Len(sender.ephemeral, 0))
+		assert.Check(t, cmp.Len(sender.posted, 0))
+	})
+
+	t.Run("cannot be deferred to the delivery window", func(t *testing.T) {
+		cfg := cfg
+		cfg.DeliveryWindow = windowOutsideNow(t)
+		cfg.DeliveryWindowPolicy = slack.DeliveryWindowPolicyDefer
+		sender := &fakeSender{}
+
+		res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+		assert.Check(t, cmp.ErrorType(err, &ConfigError{}))
+		assert.Check(t, cmp.ErrorContains(err, "ephemeral messages cannot be deferred"))
+		assert.Check(t, cmp.Equal(res.Reason, result.ReasonConfigError))
+		assert.Check(t, cmp.Len(sender.ephemeral, 0))
+		assert.Check(t, cmp.Len(sender.posted, 0))
+
+		cfg.DryRun = true
+		_, err = New(cfg, Options{Sender: sender}).Notify(ctx)
+		assert.Check(t, cmp.ErrorType(err, &ConfigError{}))
+	})
+}
+
+func TestNotifyRecipients(t *testing.T) {
+	ctx := testcontext.Background()
+	cfg := validConfig()
+	cfg.Channels = []string{"one", "user:alice@example.com", "user:U123", "group:oncall"}
+	sender := &fakeSender{groups: map[string][]string{"oncall": {"U456", "U789"}}}
+
+	res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Equal(res.Decision, result.DecisionPosted))
+	assert.Check(t, cmp.DeepEqual(sender.posted, []string{"one", "D-U-alice@example.com", "D-U123", "D-U456", "D-U789"}))
+	assert.Check(t, cmp.DeepEqual(res.Channels, []result.ChannelResult{
+		{Channel: "one", ChannelID: "ID-one", TS: "1700000000.000100"},
+		{Channel: "user:alice@example.com", ChannelID: "ID-D-U-alice@example.com", TS: "1700000000.000100", User: "U-alice@example.com"},
+		{Channel: "user:U123", ChannelID: "ID-D-U123", TS: "1700000000.000100", User: "U123"},
+		{Channel: "group:oncall", ChannelID: "ID-D-U456", TS: "1700000000.000100", User: "U456"},
+		{Channel: "group:oncall", ChannelID: "ID-D-U789", TS: "1700000000.000100", User: "U789"},
+	}))
+
+	t.Run("scheduled", func(t *testing.T) {
+		cfg := cfg
+		cfg.Channels = []string{"user:U123"}
+		cfg.PostAt = time.Now().Add(time.Hour)
+		res, err := New(cfg, Options{Sender: &fakeSender{}}).Notify(ctx)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(res.Decision, result.DecisionScheduled))
+		assert.Check(t, cmp.Equal(res.Channels[0].ScheduledMessageID, "Q-D-U123"))
+		assert.Check(t, cmp.Equal(res.Channels[0].User, "U123"))
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		cfg := cfg
+		cfg.IgnoreErrors = true
+		sender := &fakeSender{
+			groups: map[string][]string{"oncall": {"U456"}},
+			failOn: map[string]error{"alice@example.com": &slack.APIError{Code: "users_not_found"}, "D-U123": errors.New("boom")},
+		}
+		res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(res.Decision, result.DecisionFailed))
+		assert.Check(t, cmp.DeepEqual(sender.posted, []string{"one", "D-U456"}))
+		assert.Check(t, cmp.Equal(res.Channels[1].Error, "unable to look up the user alice@example.com: users_not_found"))
+		assert.Check(t, cmp.Equal(res.Channels[2].Error, "boom"))
+		assert.Check(t, cmp.Equal(res.Channels[2].User, "U123"))
+		assert.Check(t, cmp.Equal(res.Channels[3].Error, ""))
+	})
+
+	t.Run("empty group", func(t *testing.T) {
+		cfg := cfg
+		cfg.Channels = []string{"group:oncall"}
+		res, err := New(cfg, Options{Sender: &fakeSender{}}).Notify(ctx)
+		assert.Check(t, cmp.ErrorType(err, &DeliveryError{}))
+		assert.Check(t, cmp.Equal(res.Channels[0].Error, "the user group oncall has no members"))
+	})
+
+	t.Run("invalid recipient", func(t *testing.T) {
+		cfg := cfg
+		cfg.Channels = []string{"user:"}
+		_, err := New(cfg, Options{Sender: &fakeSender{}}).Notify(ctx)
+		assert.Check(t, cmp.ErrorType(err, &ConfigError{}))
+	})
+}
+
+func TestNotifyWorkspaces(t *testing.T) {
+	ctx := testcontext.Background()
+	cfg := validConfig()
+	cfg.Channels = []string{"one", "status:incidents", "status:user:U123"}
+	sender := &fakeSender{}
+	status := &fakeSender{}
+	options := Options{Sender: sender, Workspaces: map[string]Sender{"status": status}}
+
+	res, err := New(cfg, options).Notify(ctx)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.DeepEqual(sender.posted, []string{"one"}))
+	assert.Check(t, cmp.DeepEqual(status.posted, []string{"incidents", "D-U123"}))
+	assert.Check(t, cmp.DeepEqual(res.Channels, []result.ChannelResult{
+		{Channel: "one", ChannelID: "ID-one", TS: "1700000000.000100"},
+		{Channel: "status:incidents", Workspace: "status", ChannelID: "ID-incidents", TS: "1700000000.000100"},
+		{Channel: "status:user:U123", Workspace: "status", ChannelID: "ID-D-U123", TS: "1700000000.000100", User: "U123"},
+	}))
+
+	t.Run("duplicates are threaded in the same workspace", func(t *testing.T) {
+		cfg := cfg
+		cfg.Channels = []string{"status:incidents"}
+		cfg.DedupeStore = memoryStore{}
+		cfg.DedupeKey = "key"
+		cfg.DedupeWindow = time.Hour
+		cfg.DedupePolicy = dedupe.PolicyThread
+		status := &fakeSender{}
+		options := Options{Sender: &fakeSender{}, Workspaces: map[string]Sender{"status": status}}
+
+		_, err := New(cfg, options).Notify(ctx)
+		assert.NilError(t, err)
+		res, err := New(cfg, options).Notify(ctx)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.DeepEqual(status.posted, []string{"incidents", "ID-incidents"}))
+		assert.Check(t, cmp.Equal(res.Channels[0].Workspace, "status"))
+		assert.Check(t, cmp.Equal(res.Channels[0].ThreadTS, "1700000000.000100"))
+	})
+
+	t.Run("unknown workspace", func(t *testing.T) {
+		cfg := cfg
+		cfg.Channels = []string{"eng:builds"}
+		res, err := New(cfg, options).Notify(ctx)
+		assert.Check(t, cmp.ErrorType(err, &ConfigError{}))
+		assert.Check(t, cmp.ErrorContains(err, `unknown workspace "eng"`))
+		assert.Check(t, cmp.Equal(res.Reason, result.ReasonConfigError))
+	})
+}
+
+func TestNotifyDeadline(t *testing.T) {
+	cfg := validConfig()
+	cfg.Channels = []string{"one", "slow", "two", "user:U123"}
+	sender := &fakeSender{slow: map[string]bool{"slow": true}}
+
+	ctx, cancel := context.WithTimeout(testcontext.Background(), 50*time.Millisecond)
+	defer cancel()
+	res, err := New(cfg, Options{Sender: sender}).Notify(ctx)
+	var deliveryErr *DeliveryError
+	assert.Assert(t, errors.As(err, &deliveryErr))
+	assert.Check(t, cmp.Equal(deliveryErr.Channel, "slow"))
+	assert.Check(t, deliveryErr.Abandoned)
+	assert.Check(t, cmp.ErrorIs(err, context.DeadlineExceeded))
+	assert.Check(t, cmp.Equal(res.Decision, result.DecisionFailed))
+	assert.Check(t, cmp.Equal(res.Reason, result.ReasonDeadlineExceeded))
+	assert.Check(t, cmp.DeepEqual(sender.posted, []string{"one"}))
+	assert.Check(t, cmp.DeepEqual(res.Channels, []result.ChannelResult{
+		{Channel: "one", ChannelID: "ID-one", TS: "1700000000.000100"},
+		{Channel: "slow", Abandoned: true, Error: "context deadline exceeded"},
+		{Channel: "two", Abandoned: true, Error: "context deadline exceeded"},
+		{Channel: "user:U123", Abandoned: true, Error: "context deadline exceeded"},
+	}))
+
+	t.Run("ignore errors", func(t *testing.T) {
+		cfg := cfg
+		cfg.IgnoreErrors = true
+		ctx, cancel := context.WithTimeout(testcontext.Background(), 50*time.Millisecond)
+		defer cancel()
+		res, err := New(cfg, Options{Sender: &fakeSender{slow: map[string]bool{"slow": true}}}).Notify(ctx)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(res.Reason, result.ReasonDeadlineExceeded))
+		assert.Check(t, res.Channels[3].Abandoned)
+	})
+}
+
+func TestAppendContext(t *testing.T) {
+	withBlocks, err := utils.ApplyFunctionToJSON(`{"blocks": []}`, appendContext("counter"))
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Equal(withBlocks, `{"blocks":[{"elements":[{"text":"counter","type":"mrkdwn"}],"type":"context"}]}`))
+
+	withText, err := utils.ApplyFunctionToJSON(`{"text": "hello"}`, appendContext("counter"))
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Equal(withText, `{"text":"hello\ncounter"}`))
+}
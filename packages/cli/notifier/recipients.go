@@ -0,0 +1,93 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/result"
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/slack"
+)
+
+// destination is where the message is sent for an entry of the channel list.
+type destination struct {
+	// channel is the entry of the channel list the outcome is reported for.
+	channel   string
+	recipient slack.Recipient
+	// user is set when the message is sent to the user as a direct message.
+	user string
+}
+
+// resolve returns the Notifier sending to the workspace of the channel and the destinations of the channel:
+// the channel itself, or the users of a "user:" or "group:" recipient.
+func (n *Notifier) resolve(ctx context.Context, channel string) (*Notifier, []destination, error) {
+	recipient, err := slack.ParseRecipient(channel)
+	if err != nil {
+		return nil, nil, err
+	}
+	w, err := n.inWorkspace(recipient.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	if recipient.Type == slack.RecipientChannel {
+		return w, []destination{{channel: channel, recipient: recipient}}, nil
+	}
+
+	users, err := w.recipientUsers(ctx, recipient)
+	if err != nil {
+		return nil, nil, err
+	}
+	destinations := make([]destination, 0, len(users))
+	for _, user := range users {
+		destinations = append(destinations, destination{channel: channel, recipient: recipient, user: user})
+	}
+	return w, destinations, nil
+}
+
+// recipientUsers returns the IDs of the users a "user:" or "group:" recipient is sent a direct message.
+func (n *Notifier) recipientUsers(ctx context.Context, recipient slack.Recipient) ([]string, error) {
+	switch recipient.Type {
+	case slack.RecipientUser:
+		if !recipient.IsEmail() {
+			return []string{recipient.Name}, nil
+		}
+		user, err := n.sender.LookupUserByEmail(ctx, recipient.Name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to look up the user %s: %w", recipient.Name, err)
+		}
+		return []string{user}, nil
+	case slack.RecipientGroup:
+		users, err := n.sender.UserGroupMembers(ctx, recipient.Name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to look up the members of the user group %s: %w", recipient.Name, err)
+		}
+		if len(users) == 0 {
+			return nil, fmt.Errorf("the user group %s has no members", recipient.Name)
+		}
+		n.logger.Infof("Sending a direct message to the %d members of the user group %s", len(users), recipient.Name)
+		return users, nil
+	default:
+		return nil, fmt.Errorf("the recipient %s is not a user or a user group", recipient.Name)
+	}
+}
+
+// sendDirect opens the direct message with the user and posts the message there, or schedules it when
+// postAt is not zero. The result is reported for the channel, with the user it was sent to.
+func (n *Notifier) sendDirect(
+	ctx context.Context, payload, channel, user string, postAt time.Time,
+) (result.ChannelResult, error) {
+	dm, err := n.sender.OpenConversation(ctx, user)
+	if err != nil {
+		return result.ChannelResult{Channel: channel, User: user, Error: err.Error()}, err
+	}
+
+	var channelResult result.ChannelResult
+	if postAt.IsZero() {
+		channelResult, err = n.post(ctx, payload, dm)
+	} else {
+		channelResult, err = n.schedule(ctx, payload, dm, postAt)
+	}
+	channelResult.Channel = channel
+	channelResult.User = user
+	return channelResult, err
+}
@@ -0,0 +1,37 @@
+package notifier
+
+import (
+	"fmt"
+
+	"github.com/CircleCI-Public/slack-orb-go/packages/cli/slack"
+)
+
+// validateWorkspaces checks that the workspace of every configured channel has a Sender.
+// The channels requested by the commit message are checked when they are delivered.
+func (n *Notifier) validateWorkspaces() error {
+	for _, channel := range n.cfg.Channels {
+		recipient, err := slack.ParseRecipient(channel)
+		if err != nil {
+			return &ConfigError{Err: err}
+		}
+		if _, err := n.inWorkspace(recipient.Workspace); err != nil {
+			return &ConfigError{Err: err}
+		}
+	}
+	return nil
+}
+
+// inWorkspace returns a Notifier sending with the Sender of the named workspace,
+// or n itself for the default workspace.
+func (n *Notifier) inWorkspace(name string) (*Notifier, error) {
+	if name == "" {
+		return n, nil
+	}
+	sender, ok := n.workspaces[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown workspace %q", name)
+	}
+	w := *n
+	w.sender = sender
+	return &w, nil
+}
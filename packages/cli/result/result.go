@@ -0,0 +1,192 @@
+package result
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Decision describes what the CLI decided to do with a notification.
+type Decision string
+
+const (
+	DecisionPosted    Decision = "posted"
+	DecisionScheduled Decision = "scheduled"
+	DecisionSkipped   Decision = "skipped"
+	DecisionFailed    Decision = "failed"
+	DecisionDryRun    Decision = "dry_run"
+	DecisionDeleted   Decision = "deleted"
+)
+
+// Reasons reported alongside a skipped or failed decision.
+const (
+	ReasonStatusMismatch      = "status_mismatch"
+	ReasonPostConditionNotMet = "post_condition_not_met"
+	ReasonNoMatchingPaths     = "no_matching_paths"
+	ReasonOutsideWindow       = "outside_delivery_window"
+	ReasonSkippedByCommit     = "commit_directive"
+	ReasonDuplicate           = "duplicate"
+	ReasonUnknownUser         = "unknown_user"
+	ReasonTooRecent           = "too_recent"
+	ReasonAlreadyDeleted      = "already_deleted"
+	ReasonConfigError         = "config_error"
+	ReasonRenderError         = "render_error"
+	ReasonDeliveryError       = "delivery_error"
+	ReasonDeadlineExceeded    = "deadline_exceeded"
+)
+
+// FallbackDirectMessage is reported when an ephemeral message could not be shown to the user in the channel,
+// or the Slack user was unknown, so the message was sent as a direct message instead.
+const FallbackDirectMessage = "direct_message"
+
+// Result is the machine-readable summary of a notify run.
+type Result struct {
+	Decision    Decision `json:"decision"`
+	Reason      string   `json:"reason,omitempty"`
+	Error       string   `json:"error,omitempty"`
+	PayloadHash string   `json:"payload_hash,omitempty"`
+	// MatchedPaths are the changed files matching the path patterns, when there are any.
+	MatchedPaths []string `json:"matched_paths,omitempty"`
+	// Occurrences is the number of times the notification was sent within the dedupe window,
+	// including this one, when deduplication is enabled.
+	Occurrences int             `json:"occurrences,omitempty"`
+	Channels    []ChannelResult `json:"channels"`
+}
+
+// ChannelResult is the outcome of posting to a single channel.
+type ChannelResult struct {
+	Channel string `json:"channel"`
+	// Workspace is the name of the workspace the channel is in, or empty for the default workspace.
+	Workspace string `json:"workspace,omitempty"`
+	ChannelID string `json:"channel_id,omitempty"`
+	TS        string `json:"ts,omitempty"`
+	// ThreadTS is set when the message was posted as a reply in the thread of an earlier message.
+	ThreadTS  string `json:"thread_ts,omitempty"`
+	Permalink string `json:"permalink,omitempty"`
+	// ScheduledMessageID and PostAt are set instead of TS when the message was scheduled.
+	ScheduledMessageID string       `json:"scheduled_message_id,omitempty"`
+	PostAt             string       `json:"post_at,omitempty"`
+	Error              string       `json:"error,omitempty"`
+	Files              []FileResult `json:"files,omitempty"`
+	// User is the Slack user a direct message was sent to, or an ephemeral message was shown to. Fallback is set
+	// when an ephemeral message could not be shown in the channel and was sent to the user another way,
+	// such as FallbackDirectMessage.
+	User      string `json:"user,omitempty"`
+	Ephemeral bool   `json:"ephemeral,omitempty"`
+	Fallback  string `json:"fallback,omitempty"`
+	// Abandoned is set when the message was not sent, or its sending was interrupted, because the
+	// deadline of the notification was exceeded.
+	Abandoned bool `json:"abandoned,omitempty"`
+	// Deleted is set when the message was deleted by the delete command. Reason explains why it was not.
+	Deleted bool   `json:"deleted,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// FileResult is the outcome of attaching a file to the message in a channel.
+type FileResult struct {
+	Path   string `json:"path"`
+	FileID string `json:"file_id,omitempty"`
+	Size   int64  `json:"size"`
+	Error  string `json:"error,omitempty"`
+}
+
+// New returns an empty result. Channels is initialized so that it is
+// serialized as an empty list rather than null.
+func New() *Result {
+	return &Result{Channels: []ChannelResult{}}
+}
+
+// Skip marks the result as skipped for the given reason.
+func (r *Result) Skip(reason string) {
+	r.Decision = DecisionSkipped
+	r.Reason = reason
+}
+
+// Fail marks the result as failed for the given reason and error.
+func (r *Result) Fail(reason string, err error) {
+	r.Decision = DecisionFailed
+	r.Reason = reason
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+// AddChannel records the outcome for a channel. The decision is posted, or
+// scheduled if the message was scheduled, until any channel reports an error or is abandoned.
+func (r *Result) AddChannel(cr ChannelResult) {
+	r.Channels = append(r.Channels, cr)
+	switch {
+	case cr.Abandoned:
+		r.Decision = DecisionFailed
+		r.Reason = ReasonDeadlineExceeded
+	case cr.Error != "":
+		r.Decision = DecisionFailed
+		r.Reason = ReasonDeliveryError
+	case r.Decision != "":
+	case cr.ScheduledMessageID != "":
+		r.Decision = DecisionScheduled
+	default:
+		r.Decision = DecisionPosted
+	}
+}
+
+// AddDeletion records the outcome of deleting the message in a channel. The decision is deleted
+// when any message was deleted, or skipped for the reason of the first channel, until any channel
+// reports an error.
+func (r *Result) AddDeletion(cr ChannelResult) {
+	r.Channels = append(r.Channels, cr)
+	switch {
+	case cr.Error != "":
+		r.Decision = DecisionFailed
+		r.Reason = ReasonDeliveryError
+	case r.Decision == DecisionFailed:
+	case cr.Deleted:
+		r.Decision = DecisionDeleted
+		r.Reason = ""
+	case r.Decision == "":
+		r.Skip(cr.Reason)
+	}
+}
+
+// HashPayload returns the SHA256 of the rendered payload in the "sha256:<hex>" form.
+func HashPayload(payload string) string {
+	sum := sha256.Sum256([]byte(payload))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Write writes the result as indented JSON.
+func (r *Result) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteFile writes the result as indented JSON to the file at path.
+func (r *Result) WriteFile(path string) error {
+	//nolint:gosec // G304 the path is provided by the user on purpose
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unable to create result file: %w", err)
+	}
+	defer f.Close()
+
+	return r.Write(f)
+}
+
+// ReadFile reads a result previously written with WriteFile.
+func ReadFile(path string) (*Result, error) {
+	//nolint:gosec // G304 the path is provided by the user on purpose
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read result file: %w", err)
+	}
+
+	var r Result
+	if err := json.Unmarshal(content, &r); err != nil {
+		return nil, fmt.Errorf("unable to parse result file %q: %w", path, err)
+	}
+	return &r, nil
+}
@@ -0,0 +1,148 @@
+package result
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func TestAddChannel(t *testing.T) {
+	tests := []struct {
+		name             string
+		channels         []ChannelResult
+		expectedDecision Decision
+		expectedReason   string
+	}{
+		{
+			name:             "all channels posted",
+			channels:         []ChannelResult{{Channel: "a", TS: "1"}, {Channel: "b", TS: "2"}},
+			expectedDecision: DecisionPosted,
+		},
+		{
+			name:             "one channel failed",
+			channels:         []ChannelResult{{Channel: "a", TS: "1"}, {Channel: "b", Error: "channel_not_found"}},
+			expectedDecision: DecisionFailed,
+			expectedReason:   ReasonDeliveryError,
+		},
+		{
+			name:             "all channels scheduled",
+			channels:         []ChannelResult{{Channel: "a", ScheduledMessageID: "Q1"}, {Channel: "b", ScheduledMessageID: "Q2"}},
+			expectedDecision: DecisionScheduled,
+		},
+		{
+			name: "remaining channels abandoned",
+			channels: []ChannelResult{
+				{Channel: "a", TS: "1"},
+				{Channel: "b", Abandoned: true, Error: "context deadline exceeded"},
+			},
+			expectedDecision: DecisionFailed,
+			expectedReason:   ReasonDeadlineExceeded,
+		},
+		{
+			name:             "failure is not overwritten by a later success",
+			channels:         []ChannelResult{{Channel: "a", Error: "channel_not_found"}, {Channel: "b", TS: "2"}},
+			expectedDecision: DecisionFailed,
+			expectedReason:   ReasonDeliveryError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New()
+			for _, c := range tt.channels {
+				r.AddChannel(c)
+			}
+			assert.Check(t, cmp.Equal(r.Decision, tt.expectedDecision))
+			assert.Check(t, cmp.Equal(r.Reason, tt.expectedReason))
+			assert.Check(t, cmp.Len(r.Channels, len(tt.channels)))
+		})
+	}
+}
+
+func TestAddDeletion(t *testing.T) {
+	tests := []struct {
+		name             string
+		channels         []ChannelResult
+		expectedDecision Decision
+		expectedReason   string
+	}{
+		{
+			name:             "all messages deleted",
+			channels:         []ChannelResult{{Channel: "a", TS: "1", Deleted: true}, {Channel: "b", TS: "2", Deleted: true}},
+			expectedDecision: DecisionDeleted,
+		},
+		{
+			name:             "no message old enough",
+			channels:         []ChannelResult{{Channel: "a", TS: "1", Reason: ReasonTooRecent}},
+			expectedDecision: DecisionSkipped,
+			expectedReason:   ReasonTooRecent,
+		},
+		{
+			name: "some messages deleted",
+			channels: []ChannelResult{
+				{Channel: "a", TS: "1", Reason: ReasonAlreadyDeleted},
+				{Channel: "b", TS: "2", Deleted: true},
+			},
+			expectedDecision: DecisionDeleted,
+		},
+		{
+			name:             "failure is not overwritten by a later deletion",
+			channels:         []ChannelResult{{Channel: "a", Error: "cant_delete_message"}, {Channel: "b", TS: "2", Deleted: true}},
+			expectedDecision: DecisionFailed,
+			expectedReason:   ReasonDeliveryError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New()
+			for _, c := range tt.channels {
+				r.AddDeletion(c)
+			}
+			assert.Check(t, cmp.Equal(r.Decision, tt.expectedDecision))
+			assert.Check(t, cmp.Equal(r.Reason, tt.expectedReason))
+			assert.Check(t, cmp.Len(r.Channels, len(tt.channels)))
+		})
+	}
+}
+
+func TestHashPayload(t *testing.T) {
+	assert.Check(t, cmp.Equal(HashPayload(`{"text":"hi"}`), HashPayload(`{"text":"hi"}`)))
+	assert.Check(t, HashPayload(`{"text":"hi"}`) != HashPayload(`{"text":"bye"}`))
+	assert.Check(t, cmp.Equal(HashPayload(""),
+		"sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"))
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("skipped result has empty channel list", func(t *testing.T) {
+		r := New()
+		r.Skip(ReasonStatusMismatch)
+
+		var buf bytes.Buffer
+		assert.NilError(t, r.Write(&buf))
+
+		var decoded map[string]interface{}
+		assert.NilError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		assert.Check(t, cmp.Equal(decoded["decision"], "skipped"))
+		assert.Check(t, cmp.Equal(decoded["reason"], "status_mismatch"))
+		assert.Check(t, cmp.DeepEqual(decoded["channels"], []interface{}{}))
+	})
+
+	t.Run("failed result to file", func(t *testing.T) {
+		r := New()
+		r.Fail(ReasonRenderError, errors.New("the template does not exist"))
+
+		path := filepath.Join(t.TempDir(), "result.json")
+		assert.NilError(t, r.WriteFile(path))
+
+		decoded, err := ReadFile(path)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(decoded.Decision, DecisionFailed))
+		assert.Check(t, cmp.Equal(decoded.Error, "the template does not exist"))
+	})
+}
@@ -0,0 +1,73 @@
+// Package signing signs and verifies requests with the signing secret of a Slack app,
+// as described in https://api.slack.com/authentication/verifying-requests-from-slack.
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Version is the version of the signature scheme, which prefixes the signatures.
+const Version = "v0"
+
+// Headers set by Slack on the requests it sends.
+const (
+	TimestampHeader = "X-Slack-Request-Timestamp"
+	SignatureHeader = "X-Slack-Signature"
+)
+
+// DefaultMaxAge is how far the timestamp of a request can be from the current time
+// before the request is rejected as a possible replay.
+const DefaultMaxAge = 5 * time.Minute
+
+var (
+	// ErrInvalidSignature is returned when the request was not signed with the signing secret.
+	ErrInvalidSignature = errors.New("invalid request signature")
+	// ErrExpiredTimestamp is returned when the request timestamp is outside of the replay window.
+	ErrExpiredTimestamp = errors.New("the request timestamp is too old")
+)
+
+// Sign returns the signature of body sent at timestamp, a Unix time in seconds:
+// the hex encoded HMAC-SHA256 of "v0:<timestamp>:<body>" keyed with the secret, prefixed with "v0=".
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(Version + ":" + timestamp + ":"))
+	_, _ = mac.Write(body)
+	return Version + "=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SetHeaders signs body as sent at now and sets the signature headers, as Slack does.
+func SetHeaders(header http.Header, secret string, body []byte, now time.Time) {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	header.Set(TimestampHeader, timestamp)
+	header.Set(SignatureHeader, Sign(secret, timestamp, body))
+}
+
+// Verify checks that signature is the signature of body sent at timestamp,
+// and that timestamp is less than maxAge away from now.
+func Verify(secret, timestamp, signature string, body []byte, now time.Time, maxAge time.Duration) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid request timestamp %q", timestamp)
+	}
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > maxAge || age < -maxAge {
+		return ErrExpiredTimestamp
+	}
+
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyHeaders checks the signature headers of a request with body, using DefaultMaxAge.
+func VerifyHeaders(header http.Header, secret string, body []byte, now time.Time) error {
+	return Verify(secret, header.Get(TimestampHeader), header.Get(SignatureHeader), body, now, DefaultMaxAge)
+}
@@ -0,0 +1,143 @@
+package signing
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+// The example of https://api.slack.com/authentication/verifying-requests-from-slack.
+const (
+	exampleSecret    = "8f742231b10e8888abcd99yyyzzz85a5"
+	exampleTimestamp = "1531420618"
+	exampleBody      = "token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J&team_domain=testteamnow" +
+		"&channel_id=G8PSS9T3V&channel_name=foobar&user_id=U2CERLKJA&user_name=roadrunner" +
+		"&command=%2Fwebhook-collect&text=&response_url=https%3A%2F%2Fhooks.slack.com%2Fcommands" +
+		"%2FT1DC2JH3J%2F397700885554%2F96rGlfmibIGlgcZRskXaIFfN" +
+		"&trigger_id=398738663015.47445629121.803a0bc887a14d10d2c447fce8b6703c"
+	exampleSignature = "v0=a2114d57b48eac39b9ad189dd8316235a7b4a8d21a10bd27519666489c69b503"
+)
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      string
+		want      string
+	}{
+		{
+			name:      "slack example",
+			secret:    exampleSecret,
+			timestamp: exampleTimestamp,
+			body:      exampleBody,
+			want:      exampleSignature,
+		},
+		{
+			name:      "interaction payload",
+			secret:    "shhh",
+			timestamp: "1700000000",
+			body:      "payload=%7B%22type%22%3A%22block_actions%22%7D",
+			want:      "v0=af30dff322f9e2c4e6f4c5626a4f6947cc3b7af9e5c5c9d559895c392f21d9b0",
+		},
+		{
+			name:      "empty body",
+			secret:    "shhh",
+			timestamp: "1700000000",
+			body:      "",
+			want:      "v0=0b938e8e6a435ec1a84a5317994bff557914b6db0b73f0a30f73f0abc29bad18",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Check(t, cmp.Equal(Sign(tt.secret, tt.timestamp, []byte(tt.body)), tt.want))
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1531420618, 0)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      string
+		now       time.Time
+		wantErr   string
+	}{
+		{
+			name:   "slack example",
+			secret: exampleSecret, timestamp: exampleTimestamp, signature: exampleSignature, body: exampleBody, now: now,
+		},
+		{
+			name:   "within the replay window",
+			secret: exampleSecret, timestamp: exampleTimestamp, signature: exampleSignature, body: exampleBody,
+			now: now.Add(4 * time.Minute),
+		},
+		{
+			name:   "after the replay window",
+			secret: exampleSecret, timestamp: exampleTimestamp, signature: exampleSignature, body: exampleBody,
+			now:     now.Add(6 * time.Minute),
+			wantErr: ErrExpiredTimestamp.Error(),
+		},
+		{
+			name:   "in the future",
+			secret: exampleSecret, timestamp: exampleTimestamp, signature: exampleSignature, body: exampleBody,
+			now:     now.Add(-6 * time.Minute),
+			wantErr: ErrExpiredTimestamp.Error(),
+		},
+		{
+			name:   "wrong secret",
+			secret: "8f742231b10e8888abcd99yyyzzz85a6", timestamp: exampleTimestamp, signature: exampleSignature,
+			body: exampleBody, now: now,
+			wantErr: ErrInvalidSignature.Error(),
+		},
+		{
+			name:   "tampered body",
+			secret: exampleSecret, timestamp: exampleTimestamp, signature: exampleSignature, body: exampleBody + "&x=1",
+			now:     now,
+			wantErr: ErrInvalidSignature.Error(),
+		},
+		{
+			name:   "tampered timestamp",
+			secret: exampleSecret, timestamp: "1531420619", signature: exampleSignature, body: exampleBody, now: now,
+			wantErr: ErrInvalidSignature.Error(),
+		},
+		{
+			name:   "missing signature",
+			secret: exampleSecret, timestamp: exampleTimestamp, body: exampleBody, now: now,
+			wantErr: ErrInvalidSignature.Error(),
+		},
+		{
+			name:   "missing timestamp",
+			secret: exampleSecret, signature: exampleSignature, body: exampleBody, now: now,
+			wantErr: `invalid request timestamp ""`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.timestamp, tt.signature, []byte(tt.body), tt.now, DefaultMaxAge)
+			if tt.wantErr != "" {
+				assert.Check(t, cmp.Error(err, tt.wantErr))
+				return
+			}
+			assert.Check(t, err)
+		})
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	now := time.Unix(1531420618, 0)
+	header := http.Header{}
+	SetHeaders(header, exampleSecret, []byte(exampleBody), now)
+
+	assert.Check(t, cmp.Equal(header.Get(TimestampHeader), exampleTimestamp))
+	assert.Check(t, cmp.Equal(header.Get(SignatureHeader), exampleSignature))
+	assert.Check(t, VerifyHeaders(header, exampleSecret, []byte(exampleBody), now))
+	assert.Check(t, cmp.ErrorIs(VerifyHeaders(header, "other", []byte(exampleBody), now), ErrInvalidSignature))
+}
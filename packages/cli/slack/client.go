@@ -3,6 +3,10 @@ package slack
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/circleci/ex/config/secret"
@@ -11,57 +15,300 @@ import (
 	"github.com/CircleCI-Public/slack-orb-go/packages/cli/utils"
 )
 
-const defaultSlackURL = "https://slack.com/api"
+const (
+	defaultSlackURL = "https://slack.com/api"
+	// DefaultTimeout is the time a call to the Slack API can take, including its retries, unless
+	// ClientOptions.Timeout is set.
+	DefaultTimeout = 10 * time.Second
+	// DefaultUploadTimeout is the time an upload of a file contents can take, including its retries,
+	// unless ClientOptions.Timeout is set.
+	DefaultUploadTimeout = time.Minute
+)
 
 type Client struct {
-	hc *httpclient.Client
+	hc      *httpclient.Client
+	timeout time.Duration
+	// uploads sends file contents to the upload URLs returned by Slack, which are not part of the API.
+	uploads       *httpclient.Client
+	uploadTimeout time.Duration
 }
 
 type ClientOptions struct {
 	BaseURL    string
 	SlackToken secret.String
+	// Transport is used for every request to Slack, such as one returned by NewTransport.
+	// The default transport is used when it is nil.
+	Transport http.RoundTripper
+	// Timeout is the time a call to the Slack API, or an upload of a file contents, can take,
+	// including its retries. It defaults to DefaultTimeout for calls and DefaultUploadTimeout for uploads.
+	Timeout time.Duration
 }
 
 type APIResponse struct {
 	Error string `json:"error"`
 }
 
+// APIError is returned when Slack responds with an error code, such as "channel_not_found".
+type APIError struct {
+	Code string
+}
+
+func (e *APIError) Error() string {
+	return e.Code
+}
+
+// IsAPIError reports whether err is an APIError with the given code.
+func IsAPIError(err error, code string) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+type PostMessageResponse struct {
+	APIResponse
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
+
+type ScheduleMessageResponse struct {
+	APIResponse
+	Channel            string `json:"channel"`
+	ScheduledMessageID string `json:"scheduled_message_id"`
+	PostAt             int64  `json:"post_at"`
+}
+
+type PermalinkResponse struct {
+	APIResponse
+	Permalink string `json:"permalink"`
+}
+
 func NewClient(options ClientOptions) *Client {
 	baseURL := defaultSlackURL
 	if options.BaseURL != "" {
 		baseURL = options.BaseURL
 	}
+	timeout, uploadTimeout := DefaultTimeout, DefaultUploadTimeout
+	if options.Timeout > 0 {
+		timeout, uploadTimeout = options.Timeout, options.Timeout
+	}
 	hc := httpclient.New(httpclient.Config{
 		Name:       "Slack Client",
 		BaseURL:    baseURL,
 		AuthToken:  options.SlackToken.Value(),
 		AcceptType: httpclient.JSON,
-		Timeout:    time.Second * 10,
+		Timeout:    timeout,
+		Transport:  options.Transport,
+	})
+	uploads := httpclient.New(httpclient.Config{
+		Name:      "Slack Upload Client",
+		Timeout:   uploadTimeout,
+		Transport: options.Transport,
 	})
 
-	return &Client{hc}
+	return &Client{hc: hc, timeout: timeout, uploads: uploads, uploadTimeout: uploadTimeout}
 }
 
-func (c *Client) PostMessage(ctx context.Context, message, channel string) error {
+// call sends the request to the Slack API. Every attempt takes at most the timeout of the client,
+// and so do all of them together.
+func (c *Client) call(ctx context.Context, req httpclient.Request) error {
+	httpclient.Timeout(c.timeout)(&req)
+	return c.hc.Call(ctx, req)
+}
+
+// PostMessage posts the message to the channel and returns the channel ID
+// and timestamp Slack assigned to it.
+func (c *Client) PostMessage(ctx context.Context, message, channel string) (*PostMessageResponse, error) {
 	jsonWithChannel, err := utils.ApplyFunctionToJSON(message, utils.AddRootProperty("channel", channel))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var response APIResponse
+	var response PostMessageResponse
 	req := httpclient.NewRequest("POST", "/chat.postMessage",
 		httpclient.Header("Content-Type", httpclient.JSON), // explicitly required by Slack when a post body is sent
 		httpclient.RawBody([]byte(jsonWithChannel)),
 		httpclient.JSONDecoder(&response),
 	)
 
-	err = c.hc.Call(ctx, req)
+	err = c.call(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != "" {
+		return nil, &APIError{Code: response.Error}
+	}
+	return &response, nil
+}
+
+type PostEphemeralResponse struct {
+	APIResponse
+	MessageTS string `json:"message_ts"`
+}
+
+// PostEphemeral posts the message to the channel so that only user sees it, and returns its timestamp.
+// Slack responds with "user_not_in_channel" when the user is not a member of the channel.
+func (c *Client) PostEphemeral(ctx context.Context, message, channel, user string) (string, error) {
+	jsonWithChannel, err := utils.ApplyFunctionToJSON(message, utils.AddRootProperty("channel", channel))
+	if err != nil {
+		return "", err
+	}
+	jsonWithUser, err := utils.ApplyFunctionToJSON(jsonWithChannel, utils.AddRootProperty("user", user))
+	if err != nil {
+		return "", err
+	}
+
+	var response PostEphemeralResponse
+	req := httpclient.NewRequest("POST", "/chat.postEphemeral",
+		httpclient.Header("Content-Type", httpclient.JSON), // explicitly required by Slack when a post body is sent
+		httpclient.RawBody([]byte(jsonWithUser)),
+		httpclient.JSONDecoder(&response),
+	)
+
+	err = c.call(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	if response.Error != "" {
+		return "", &APIError{Code: response.Error}
+	}
+	return response.MessageTS, nil
+}
+
+// UpdateMessage replaces the content of the message identified by channel and ts.
+func (c *Client) UpdateMessage(ctx context.Context, message, channel, ts string) error {
+	jsonWithChannel, err := utils.ApplyFunctionToJSON(message, utils.AddRootProperty("channel", channel))
+	if err != nil {
+		return err
+	}
+	jsonWithTS, err := utils.ApplyFunctionToJSON(jsonWithChannel, utils.AddRootProperty("ts", ts))
+	if err != nil {
+		return err
+	}
+
+	var response APIResponse
+	req := httpclient.NewRequest("POST", "/chat.update",
+		httpclient.Header("Content-Type", httpclient.JSON), // explicitly required by Slack when a post body is sent
+		httpclient.RawBody([]byte(jsonWithTS)),
+		httpclient.JSONDecoder(&response),
+	)
+
+	err = c.call(ctx, req)
 	if err != nil {
 		return err
 	}
 
 	if response.Error != "" {
-		return errors.New(response.Error)
+		return &APIError{Code: response.Error}
 	}
 	return nil
 }
+
+// GetPermalink returns the permanent URL of the message identified by channel and ts.
+func (c *Client) GetPermalink(ctx context.Context, channel, ts string) (string, error) {
+	var response PermalinkResponse
+	req := httpclient.NewRequest("GET", "/chat.getPermalink",
+		httpclient.QueryParam("channel", channel),
+		httpclient.QueryParam("message_ts", ts),
+		httpclient.JSONDecoder(&response),
+	)
+
+	err := c.call(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	if response.Error != "" {
+		return "", &APIError{Code: response.Error}
+	}
+	return response.Permalink, nil
+}
+
+// ScheduleMessage schedules the message to be posted to the channel at postAt
+// and returns the ID Slack assigned to the scheduled message.
+func (c *Client) ScheduleMessage(ctx context.Context, message, channel string, postAt time.Time) (*ScheduleMessageResponse, error) {
+	jsonWithChannel, err := utils.ApplyFunctionToJSON(message, utils.AddRootProperty("channel", channel))
+	if err != nil {
+		return nil, err
+	}
+	jsonWithPostAt, err := utils.ApplyFunctionToJSON(jsonWithChannel, utils.AddRootProperty("post_at", postAt.Unix()))
+	if err != nil {
+		return nil, err
+	}
+
+	var response ScheduleMessageResponse
+	req := httpclient.NewRequest("POST", "/chat.scheduleMessage",
+		httpclient.Header("Content-Type", httpclient.JSON), // explicitly required by Slack when a post body is sent
+		httpclient.RawBody([]byte(jsonWithPostAt)),
+		httpclient.JSONDecoder(&response),
+	)
+
+	err = c.call(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != "" {
+		return nil, &APIError{Code: response.Error}
+	}
+	return &response, nil
+}
+
+// DeleteScheduledMessage cancels a message scheduled with ScheduleMessage before it is posted.
+func (c *Client) DeleteScheduledMessage(ctx context.Context, channel, scheduledMessageID string) error {
+	body := map[string]string{
+		"channel":              channel,
+		"scheduled_message_id": scheduledMessageID,
+	}
+
+	var response APIResponse
+	req := httpclient.NewRequest("POST", "/chat.deleteScheduledMessage",
+		httpclient.Body(body),
+		httpclient.JSONDecoder(&response),
+	)
+
+	err := c.call(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if response.Error != "" {
+		return &APIError{Code: response.Error}
+	}
+	return nil
+}
+
+// DeleteMessage deletes the message identified by channel and ts.
+// Slack responds with "message_not_found" when the message was already deleted.
+func (c *Client) DeleteMessage(ctx context.Context, channel, ts string) error {
+	body := map[string]string{
+		"channel": channel,
+		"ts":      ts,
+	}
+
+	var response APIResponse
+	req := httpclient.NewRequest("POST", "/chat.delete",
+		httpclient.Body(body),
+		httpclient.JSONDecoder(&response),
+	)
+
+	err := c.call(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if response.Error != "" {
+		return &APIError{Code: response.Error}
+	}
+	return nil
+}
+
+// MessageTime returns the time a message was posted, which is the integer part of its timestamp.
+func MessageTime(ts string) (time.Time, error) {
+	seconds, _, _ := strings.Cut(ts, ".")
+	unix, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid message timestamp %q", ts)
+	}
+	return time.Unix(unix, 0), nil
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/circleci/ex/testing/httprecorder"
 	"github.com/circleci/ex/testing/testcontext"
@@ -42,7 +43,7 @@ func Test_Post_Message(t *testing.T) {
 		if auth := r.Header.Get("Authorization"); auth == "" {
 			_, _ = w.Write([]byte(`{"error": "not_authed"}`))
 		} else {
-			_, _ = w.Write([]byte(`{"ok": true}`))
+			_, _ = w.Write([]byte(`{"ok": true, "channel": "C123", "ts": "1700000000.000100"}`))
 		}
 
 	}))
@@ -50,14 +51,289 @@ func Test_Post_Message(t *testing.T) {
 
 	t.Run("successful", func(t *testing.T) {
 		client := NewClient(ClientOptions{BaseURL: server.URL, SlackToken: "faketoken"})
-		err := client.PostMessage(ctx, `{"text": "Hello, world!"}`, "test_channel")
+		resp, err := client.PostMessage(ctx, `{"text": "Hello, world!"}`, "test_channel")
 		assert.NilError(t, err)
 		assert.Check(t, cmp.Contains(recorder.LastRequest().Header["Authorization"], "Bearer faketoken"))
+		assert.Check(t, cmp.Equal(resp.Channel, "C123"))
+		assert.Check(t, cmp.Equal(resp.TS, "1700000000.000100"))
 	})
 
 	t.Run("not_authed", func(t *testing.T) {
 		client := NewClient(ClientOptions{BaseURL: server.URL, SlackToken: ""})
-		err := client.PostMessage(ctx, `{"text": "Hello, world!"}`, "test_channel")
+		_, err := client.PostMessage(ctx, `{"text": "Hello, world!"}`, "test_channel")
 		assert.ErrorContains(t, err, "not_authed")
 	})
 }
+
+func Test_Get_Permalink(t *testing.T) {
+	ctx := testcontext.Background()
+	recorder := httprecorder.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := recorder.Record(r)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		query := r.URL.Query()
+		if query.Get("channel") != "C123" {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "channel_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "permalink": "https://example.slack.com/archives/C123/p` +
+			query.Get("message_ts") + `"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(ClientOptions{BaseURL: server.URL, SlackToken: "faketoken"})
+
+	t.Run("successful", func(t *testing.T) {
+		permalink, err := client.GetPermalink(ctx, "C123", "1700000000.000100")
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(permalink, "https://example.slack.com/archives/C123/p1700000000.000100"))
+		assert.Check(t, cmp.Equal(recorder.LastRequest().Method, "GET"))
+	})
+
+	t.Run("channel_not_found", func(t *testing.T) {
+		_, err := client.GetPermalink(ctx, "C999", "1700000000.000100")
+		assert.ErrorContains(t, err, "channel_not_found")
+	})
+}
+
+func Test_Schedule_Message(t *testing.T) {
+	ctx := testcontext.Background()
+	recorder := httprecorder.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := recorder.Record(r)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		request := &struct {
+			Channel string `json:"channel"`
+			PostAt  int64  `json:"post_at"`
+		}{}
+		bodyBytes, _ := io.ReadAll(r.Body)
+		err = json.Unmarshal(bodyBytes, &request)
+		if err != nil || request.Channel == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if request.PostAt < time.Now().Unix() {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "time_in_past"}`))
+			return
+		}
+		response, _ := json.Marshal(map[string]interface{}{
+			"ok":                   true,
+			"channel":              "C123",
+			"scheduled_message_id": "Q1298393284",
+			"post_at":              request.PostAt,
+		})
+		_, _ = w.Write(response)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(ClientOptions{BaseURL: server.URL, SlackToken: "faketoken"})
+
+	t.Run("successful", func(t *testing.T) {
+		postAt := time.Now().Add(time.Hour).Truncate(time.Second)
+		resp, err := client.ScheduleMessage(ctx, `{"text": "Hello, world!"}`, "test_channel", postAt)
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(resp.ScheduledMessageID, "Q1298393284"))
+		assert.Check(t, cmp.Equal(resp.PostAt, postAt.Unix()))
+		assert.Check(t, cmp.Equal(recorder.LastRequest().URL.Path, "/chat.scheduleMessage"))
+	})
+
+	t.Run("time_in_past", func(t *testing.T) {
+		_, err := client.ScheduleMessage(ctx, `{"text": "Hello, world!"}`, "test_channel", time.Now().Add(-time.Hour))
+		assert.ErrorContains(t, err, "time_in_past")
+	})
+}
+
+func Test_Delete_Scheduled_Message(t *testing.T) {
+	ctx := testcontext.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &struct {
+			Channel            string `json:"channel"`
+			ScheduledMessageID string `json:"scheduled_message_id"`
+		}{}
+		bodyBytes, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(bodyBytes, &request)
+
+		if request.ScheduledMessageID != "Q1298393284" {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "invalid_scheduled_message_id"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(ClientOptions{BaseURL: server.URL, SlackToken: "faketoken"})
+
+	t.Run("successful", func(t *testing.T) {
+		err := client.DeleteScheduledMessage(ctx, "C123", "Q1298393284")
+		assert.NilError(t, err)
+	})
+
+	t.Run("invalid_scheduled_message_id", func(t *testing.T) {
+		err := client.DeleteScheduledMessage(ctx, "C123", "Q0")
+		assert.Check(t, IsAPIError(err, "invalid_scheduled_message_id"))
+	})
+}
+
+func Test_Update_Message(t *testing.T) {
+	ctx := testcontext.Background()
+	var request struct {
+		Channel string `json:"channel"`
+		TS      string `json:"ts"`
+		Text    string `json:"text"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyBytes, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(bodyBytes, &request)
+
+		if request.TS != "1700000000.000100" {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "message_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "channel": "C123", "ts": "1700000000.000100"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(ClientOptions{BaseURL: server.URL, SlackToken: "faketoken"})
+
+	t.Run("successful", func(t *testing.T) {
+		err := client.UpdateMessage(ctx, `{"text": "Failed 2 times"}`, "C123", "1700000000.000100")
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(request.Channel, "C123"))
+		assert.Check(t, cmp.Equal(request.Text, "Failed 2 times"))
+	})
+
+	t.Run("message_not_found", func(t *testing.T) {
+		err := client.UpdateMessage(ctx, `{"text": "Failed 2 times"}`, "C123", "1700000000.000200")
+		assert.Check(t, IsAPIError(err, "message_not_found"))
+	})
+}
+
+func Test_Delete_Message(t *testing.T) {
+	ctx := testcontext.Background()
+	var request struct {
+		Channel string `json:"channel"`
+		TS      string `json:"ts"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyBytes, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(bodyBytes, &request)
+
+		if request.TS != "1700000000.000100" {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "message_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "channel": "C123", "ts": "1700000000.000100"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(ClientOptions{BaseURL: server.URL, SlackToken: "faketoken"})
+
+	t.Run("successful", func(t *testing.T) {
+		err := client.DeleteMessage(ctx, "C123", "1700000000.000100")
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(request.Channel, "C123"))
+	})
+
+	t.Run("message_not_found", func(t *testing.T) {
+		err := client.DeleteMessage(ctx, "C123", "1700000000.000200")
+		assert.Check(t, IsAPIError(err, "message_not_found"))
+	})
+}
+
+func Test_Message_Time(t *testing.T) {
+	posted, err := MessageTime("1700000000.000100")
+	assert.NilError(t, err)
+	assert.Check(t, posted.Equal(time.Unix(1700000000, 0)))
+
+	_, err = MessageTime("")
+	assert.Check(t, cmp.Error(err, `invalid message timestamp ""`))
+}
+
+func Test_Post_Ephemeral(t *testing.T) {
+	ctx := testcontext.Background()
+	var request struct {
+		Channel string `json:"channel"`
+		User    string `json:"user"`
+		Text    string `json:"text"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyBytes, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(bodyBytes, &request)
+
+		if request.User != "U123" {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "user_not_in_channel"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "message_ts": "1700000000.000100"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(ClientOptions{BaseURL: server.URL, SlackToken: "faketoken"})
+
+	t.Run("successful", func(t *testing.T) {
+		ts, err := client.PostEphemeral(ctx, `{"text": "Hello, world!"}`, "C123", "U123")
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(ts, "1700000000.000100"))
+		assert.Check(t, cmp.Equal(request.Channel, "C123"))
+		assert.Check(t, cmp.Equal(request.Text, "Hello, world!"))
+	})
+
+	t.Run("user_not_in_channel", func(t *testing.T) {
+		_, err := client.PostEphemeral(ctx, `{"text": "Hello, world!"}`, "C123", "U456")
+		assert.Check(t, IsAPIError(err, "user_not_in_channel"))
+	})
+}
+
+func Test_Timeout(t *testing.T) {
+	ctx := testcontext.Background()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	mux.HandleFunc("/chat.postMessage", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/chat.update", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	mux.HandleFunc("/files.getUploadURLExternal", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "upload_url": "` + server.URL + `/upload/F123", "file_id": "F123"}`))
+	})
+	mux.HandleFunc("/upload/F123", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+
+	client := NewClient(ClientOptions{BaseURL: server.URL, SlackToken: "faketoken", Timeout: 200 * time.Millisecond})
+
+	t.Run("retries", func(t *testing.T) {
+		start := time.Now()
+		_, err := client.PostMessage(ctx, `{"text": "hello"}`, "C123")
+		assert.Check(t, err != nil)
+		assert.Check(t, time.Since(start) < 2*time.Second, "the retries did not stop after the timeout")
+	})
+
+	t.Run("slow response", func(t *testing.T) {
+		start := time.Now()
+		err := client.UpdateMessage(ctx, `{"text": "hello"}`, "C123", "1700000000.000100")
+		assert.Check(t, err != nil)
+		assert.Check(t, time.Since(start) < 2*time.Second, "the request did not stop after the timeout")
+	})
+
+	t.Run("slow upload", func(t *testing.T) {
+		start := time.Now()
+		_, err := client.UploadFile(ctx, UploadFileOptions{Filename: "test.log", Content: []byte("content"), ChannelID: "C123"})
+		assert.Check(t, err != nil)
+		assert.Check(t, time.Since(start) < 2*time.Second, "the upload did not stop after the timeout")
+	})
+}
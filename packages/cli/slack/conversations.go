@@ -0,0 +1,35 @@
+package slack
+
+import (
+	"context"
+	"strings"
+
+	"github.com/circleci/ex/httpclient"
+)
+
+type openConversationResponse struct {
+	APIResponse
+	Channel struct {
+		ID string `json:"id"`
+	} `json:"channel"`
+}
+
+// OpenConversation opens, or resumes, the direct message with the users and returns the ID of its channel.
+// Messages posted to that channel are delivered to the users as direct messages.
+func (c *Client) OpenConversation(ctx context.Context, users ...string) (string, error) {
+	var response openConversationResponse
+	req := httpclient.NewRequest("POST", "/conversations.open",
+		httpclient.Body(map[string]string{"users": strings.Join(users, ",")}),
+		httpclient.JSONDecoder(&response),
+	)
+
+	err := c.call(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	if response.Error != "" {
+		return "", &APIError{Code: response.Error}
+	}
+	return response.Channel.ID, nil
+}
@@ -0,0 +1,45 @@
+package slack
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/circleci/ex/testing/testcontext"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func Test_Open_Conversation(t *testing.T) {
+	ctx := testcontext.Background()
+	var request struct {
+		Users string `json:"users"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodyBytes, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(bodyBytes, &request)
+
+		if request.Users == "U404" {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "user_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "channel": {"id": "D123"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(ClientOptions{BaseURL: server.URL, SlackToken: "faketoken"})
+
+	t.Run("successful", func(t *testing.T) {
+		channel, err := client.OpenConversation(ctx, "U123", "U456")
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(channel, "D123"))
+		assert.Check(t, cmp.Equal(request.Users, "U123,U456"))
+	})
+
+	t.Run("user_not_found", func(t *testing.T) {
+		_, err := client.OpenConversation(ctx, "U404")
+		assert.Check(t, IsAPIError(err, "user_not_found"))
+	})
+}
@@ -0,0 +1,61 @@
+package slack
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Modes deciding how channels requested by a commit message are combined with the configured channels.
+const (
+	// CommitChannelModeAppend posts to the requested channels in addition to the configured channels.
+	CommitChannelModeAppend = "append"
+	// CommitChannelModeReplace posts to the requested channels instead of the configured channels.
+	CommitChannelModeReplace = "replace"
+)
+
+var (
+	skipDirective    = regexp.MustCompile(`(?i)\[skip slack\]`)
+	channelDirective = regexp.MustCompile(`(?i)\[slack:([^\]]+)\]`)
+)
+
+// CommitDirectives are the instructions found in a commit message.
+type CommitDirectives struct {
+	// Skip is set by "[skip slack]".
+	Skip bool
+	// Channels are set by "[slack:#channel]" or "[slack:#one,#two]", without the leading "#".
+	Channels []string
+}
+
+// ParseCommitDirectives returns the directives found in a commit message.
+func ParseCommitDirectives(message string) CommitDirectives {
+	d := CommitDirectives{Skip: skipDirective.MatchString(message)}
+	for _, match := range channelDirective.FindAllStringSubmatch(message, -1) {
+		for _, channel := range strings.Split(match[1], ",") {
+			if channel = strings.TrimPrefix(strings.TrimSpace(channel), "#"); channel != "" {
+				d.Channels = append(d.Channels, channel)
+			}
+		}
+	}
+	return d
+}
+
+// ApplyChannels returns the channels to post to once the directive channels are combined
+// with the configured channels according to mode.
+func (d CommitDirectives) ApplyChannels(channels []string, mode string) []string {
+	if len(d.Channels) == 0 {
+		return channels
+	}
+	if mode == CommitChannelModeReplace {
+		channels = nil
+	}
+
+	var result []string
+	seen := map[string]bool{}
+	for _, channel := range append(append([]string{}, channels...), d.Channels...) {
+		if !seen[channel] {
+			seen[channel] = true
+			result = append(result, channel)
+		}
+	}
+	return result
+}
@@ -0,0 +1,45 @@
+package slack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommitDirectives(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected CommitDirectives
+	}{
+		{name: "no directives", message: "Fix the build", expected: CommitDirectives{}},
+		{name: "skip", message: "Update the README [skip slack]", expected: CommitDirectives{Skip: true}},
+		{name: "skip is case insensitive", message: "Update the README\n\n[Skip Slack]", expected: CommitDirectives{Skip: true}},
+		{name: "skip ci is ignored", message: "Update the README [skip ci]", expected: CommitDirectives{}},
+		{
+			name:     "channels",
+			message:  "Release the API [slack:#api-team] [slack:#releases, ops]",
+			expected: CommitDirectives{Channels: []string{"api-team", "releases", "ops"}},
+		},
+		{
+			name:     "skip and channel",
+			message:  "[skip slack] [slack:#api-team]",
+			expected: CommitDirectives{Skip: true, Channels: []string{"api-team"}},
+		},
+		{name: "empty channel", message: "[slack:#]", expected: CommitDirectives{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseCommitDirectives(tt.message))
+		})
+	}
+}
+
+func TestCommitDirectivesApplyChannels(t *testing.T) {
+	d := CommitDirectives{Channels: []string{"api-team", "general"}}
+
+	assert.Equal(t, []string{"general", "deploys", "api-team"}, d.ApplyChannels([]string{"general", "deploys"}, CommitChannelModeAppend))
+	assert.Equal(t, []string{"api-team", "general"}, d.ApplyChannels([]string{"general", "deploys"}, CommitChannelModeReplace))
+	assert.Equal(t, []string{"general"}, CommitDirectives{}.ApplyChannels([]string{"general"}, CommitChannelModeReplace))
+}
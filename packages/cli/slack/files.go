@@ -0,0 +1,94 @@
+package slack
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/circleci/ex/httpclient"
+)
+
+// MaxFileSize is the largest file Slack accepts.
+const MaxFileSize = 1 << 30
+
+// UploadFileOptions describes a file to upload and where to share it.
+type UploadFileOptions struct {
+	Filename string
+	Title    string
+	Content  []byte
+	// ChannelID is the ID of the channel to share the file in. Channel names are not accepted.
+	ChannelID string
+	// ThreadTS shares the file as a reply in the thread of that message when it is set.
+	ThreadTS string
+}
+
+type uploadURLResponse struct {
+	APIResponse
+	UploadURL string `json:"upload_url"`
+	FileID    string `json:"file_id"`
+}
+
+type completeUploadFile struct {
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
+}
+
+type completeUploadRequest struct {
+	Files     []completeUploadFile `json:"files"`
+	ChannelID string               `json:"channel_id,omitempty"`
+	ThreadTS  string               `json:"thread_ts,omitempty"`
+}
+
+// UploadFile uploads a file with Slack's external upload flow and shares it in a channel.
+// It returns the ID of the uploaded file.
+func (c *Client) UploadFile(ctx context.Context, options UploadFileOptions) (string, error) {
+	// files.getUploadURLExternal only accepts form encoded arguments
+	form := url.Values{}
+	form.Set("filename", options.Filename)
+	form.Set("length", strconv.Itoa(len(options.Content)))
+
+	var uploadURL uploadURLResponse
+	req := httpclient.NewRequest("POST", "/files.getUploadURLExternal",
+		httpclient.Header("Content-Type", "application/x-www-form-urlencoded"),
+		httpclient.RawBody([]byte(form.Encode())),
+		httpclient.JSONDecoder(&uploadURL),
+	)
+	if err := c.call(ctx, req); err != nil {
+		return "", err
+	}
+	if uploadURL.Error != "" {
+		return "", &APIError{Code: uploadURL.Error}
+	}
+
+	req = httpclient.NewRequest("POST", "%s",
+		httpclient.RouteParams(uploadURL.UploadURL),
+		httpclient.Header("Content-Type", "application/octet-stream"),
+		httpclient.RawBody(options.Content),
+		httpclient.Timeout(c.uploadTimeout),
+	)
+	if err := c.uploads.Call(ctx, req); err != nil {
+		return "", err
+	}
+
+	title := options.Title
+	if title == "" {
+		title = options.Filename
+	}
+	var response APIResponse
+	req = httpclient.NewRequest("POST", "/files.completeUploadExternal",
+		httpclient.Body(completeUploadRequest{
+			Files:     []completeUploadFile{{ID: uploadURL.FileID, Title: title}},
+			ChannelID: options.ChannelID,
+			ThreadTS:  options.ThreadTS,
+		}),
+		httpclient.JSONDecoder(&response),
+	)
+	if err := c.call(ctx, req); err != nil {
+		return "", err
+	}
+	if response.Error != "" {
+		return "", &APIError{Code: response.Error}
+	}
+
+	return uploadURL.FileID, nil
+}
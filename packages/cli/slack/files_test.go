@@ -0,0 +1,78 @@
+package slack
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/circleci/ex/testing/testcontext"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func Test_Upload_File(t *testing.T) {
+	ctx := testcontext.Background()
+
+	var (
+		uploaded []byte
+		complete completeUploadRequest
+		length   string
+	)
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/files.getUploadURLExternal", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "not_authed"}`))
+			return
+		}
+		length = r.FormValue("length")
+		_, _ = w.Write([]byte(`{"ok": true, "upload_url": "` + server.URL + `/upload/F123", "file_id": "F123"}`))
+	})
+	mux.HandleFunc("/upload/F123", func(w http.ResponseWriter, r *http.Request) {
+		uploaded, _ = io.ReadAll(r.Body)
+	})
+	mux.HandleFunc("/files.completeUploadExternal", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &complete)
+		if complete.ChannelID == "C404" {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "channel_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	})
+
+	t.Run("successful", func(t *testing.T) {
+		client := NewClient(ClientOptions{BaseURL: server.URL, SlackToken: "faketoken"})
+		fileID, err := client.UploadFile(ctx, UploadFileOptions{
+			Filename:  "test.log",
+			Content:   []byte("FAIL: TestSomething"),
+			ChannelID: "C123",
+			ThreadTS:  "1700000000.000100",
+		})
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(fileID, "F123"))
+		assert.Check(t, cmp.Equal(length, "19"))
+		assert.Check(t, cmp.Equal(string(uploaded), "FAIL: TestSomething"))
+		assert.Check(t, cmp.DeepEqual(complete, completeUploadRequest{
+			Files:     []completeUploadFile{{ID: "F123", Title: "test.log"}},
+			ChannelID: "C123",
+			ThreadTS:  "1700000000.000100",
+		}))
+	})
+
+	t.Run("not_authed", func(t *testing.T) {
+		client := NewClient(ClientOptions{BaseURL: server.URL})
+		_, err := client.UploadFile(ctx, UploadFileOptions{Filename: "test.log", Content: []byte("content"), ChannelID: "C123"})
+		assert.Check(t, IsAPIError(err, "not_authed"))
+	})
+
+	t.Run("channel_not_found", func(t *testing.T) {
+		client := NewClient(ClientOptions{BaseURL: server.URL, SlackToken: "faketoken"})
+		_, err := client.UploadFile(ctx, UploadFileOptions{Filename: "test.log", Content: []byte("content"), ChannelID: "C404"})
+		assert.Check(t, IsAPIError(err, "channel_not_found"))
+	})
+}
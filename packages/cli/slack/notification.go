@@ -3,6 +3,7 @@ package slack
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/CircleCI-Public/slack-orb-go/packages/cli/templates"
 	"github.com/CircleCI-Public/slack-orb-go/packages/cli/utils"
@@ -20,6 +21,21 @@ type Notification struct {
 	TemplatePath   string
 	TemplateInline string
 	TemplateName   string
+
+	// DeliveryWindow restricts when notifications are delivered. It is ignored when nil.
+	DeliveryWindow       *DeliveryWindow
+	DeliveryWindowPolicy string
+	// PostAt is when the message is scheduled to be sent, or zero to send it now.
+	// The delivery window is checked at that time.
+	PostAt time.Time
+
+	// CommitMessage is checked for the "[skip slack]" directive.
+	CommitMessage string
+
+	// PathPatterns are glob patterns matched against ChangedFiles. When set,
+	// the notification is only sent if a changed file matches one of them.
+	PathPatterns []string
+	ChangedFiles []string
 }
 
 func (j *Notification) IsEventMatchingStatus() bool {
@@ -33,9 +49,53 @@ func (j *Notification) IsPostConditionMet() bool {
 
 }
 
+// MatchingPaths returns the changed files matching the path patterns.
+func (j *Notification) MatchingPaths() []string {
+	var matches []string
+	for _, file := range j.ChangedFiles {
+		for _, pattern := range j.PathPatterns {
+			// Invalid patterns are reported when the configuration is validated
+			if re, err := utils.CompileGlob(pattern); err == nil && re.MatchString(file) {
+				matches = append(matches, file)
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// IsPathConditionMet reports whether a changed file matches the path patterns, if there are any.
+func (j *Notification) IsPathConditionMet() bool {
+	return len(j.PathPatterns) == 0 || len(j.MatchingPaths()) > 0
+}
+
+// IsCommitDirectiveMet reports whether the commit message lets the notification through.
+func (j *Notification) IsCommitDirectiveMet() bool {
+	return !ParseCommitDirectives(j.CommitMessage).Skip
+}
+
+// IsDeliveryWindowMet reports whether the notification may be delivered at the given time. Outside the
+// delivery window, it is met only when the policy defers the notification, or lets failures through.
+func (j *Notification) IsDeliveryWindowMet(at time.Time) bool {
+	if j.DeliveryWindow == nil || j.DeliveryWindow.Contains(at) {
+		return true
+	}
+	switch j.DeliveryWindowPolicy {
+	case DeliveryWindowPolicyDefer:
+		return true
+	case DeliveryWindowPolicyFailuresOnly:
+		return j.Status == "fail"
+	default:
+		return false
+	}
+}
+
 var (
-	ErrStatusMismatch      = errors.New("job status does not match configured trigger")
-	ErrPostConditionNotMet = errors.New("post condition is not met")
+	ErrStatusMismatch        = errors.New("job status does not match configured trigger")
+	ErrPostConditionNotMet   = errors.New("post condition is not met")
+	ErrOutsideDeliveryWindow = errors.New("outside of the delivery window")
+	ErrNoMatchingPaths       = errors.New("no changed file matches the path patterns")
+	ErrSkippedByCommit       = errors.New("the commit message asks to skip the notification")
 )
 
 func (j *Notification) BuildMessageBody() (string, error) {
@@ -62,5 +122,21 @@ func (j *Notification) BuildMessageBody() (string, error) {
 		return "", ErrPostConditionNotMet
 	}
 
+	if !j.IsPathConditionMet() {
+		return "", ErrNoMatchingPaths
+	}
+
+	if !j.IsCommitDirectiveMet() {
+		return "", ErrSkippedByCommit
+	}
+
+	sendAt := j.PostAt
+	if sendAt.IsZero() {
+		sendAt = time.Now()
+	}
+	if !j.IsDeliveryWindowMet(sendAt) {
+		return "", ErrOutsideDeliveryWindow
+	}
+
 	return templateWithExpandedVars, nil
 }
@@ -2,6 +2,7 @@ package slack
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -98,3 +99,105 @@ func TestIsPostConditionMet(t *testing.T) {
 		})
 	}
 }
+
+func TestIsDeliveryWindowMet(t *testing.T) {
+	window, err := ParseDeliveryWindow("Mon-Fri 08:00-19:00", time.UTC)
+	assert.NoError(t, err)
+	inside := time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC)
+	outside := time.Date(2024, time.January, 15, 3, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		window *DeliveryWindow
+		policy string
+		status string
+		now    time.Time
+		want   bool
+	}{
+		{
+			name:   "no delivery window",
+			window: nil,
+			status: "pass",
+			now:    outside,
+			want:   true,
+		},
+		{
+			name:   "inside the delivery window",
+			window: window,
+			policy: DeliveryWindowPolicySkip,
+			status: "pass",
+			now:    inside,
+			want:   true,
+		},
+		{
+			name:   "outside the delivery window",
+			window: window,
+			policy: DeliveryWindowPolicySkip,
+			status: "fail",
+			now:    outside,
+			want:   false,
+		},
+		{
+			name:   "outside the delivery window when deferred",
+			window: window,
+			policy: DeliveryWindowPolicyDefer,
+			status: "pass",
+			now:    outside,
+			want:   true,
+		},
+		{
+			name:   "failure outside the delivery window when failures are let through",
+			window: window,
+			policy: DeliveryWindowPolicyFailuresOnly,
+			status: "fail",
+			now:    outside,
+			want:   true,
+		},
+		{
+			name:   "pass outside the delivery window when failures are let through",
+			window: window,
+			policy: DeliveryWindowPolicyFailuresOnly,
+			status: "pass",
+			now:    outside,
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sn := Notification{
+				Status:               tt.status,
+				DeliveryWindow:       tt.window,
+				DeliveryWindowPolicy: tt.policy,
+			}
+			got := sn.IsDeliveryWindowMet(tt.now)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsPathConditionMet(t *testing.T) {
+	changed := []string{"services/api/main.go", "README.md"}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		changed  []string
+		matches  []string
+		want     bool
+	}{
+		{name: "no patterns", changed: changed, want: true},
+		{name: "matching pattern", patterns: []string{"services/api/**"}, changed: changed, matches: []string{"services/api/main.go"}, want: true},
+		{name: "one of several patterns", patterns: []string{"services/web/**", "*.md"}, changed: changed, matches: []string{"README.md"}, want: true},
+		{name: "no matching file", patterns: []string{"services/web/**"}, changed: changed, want: false},
+		{name: "no changed file", patterns: []string{"services/api/**"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sn := Notification{PathPatterns: tt.patterns, ChangedFiles: tt.changed}
+			assert.Equal(t, tt.want, sn.IsPathConditionMet())
+			assert.Equal(t, tt.matches, sn.MatchingPaths())
+		})
+	}
+}
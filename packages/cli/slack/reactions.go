@@ -0,0 +1,45 @@
+package slack
+
+import (
+	"context"
+	"strings"
+
+	"github.com/circleci/ex/httpclient"
+)
+
+type reactionRequest struct {
+	Channel   string `json:"channel"`
+	Timestamp string `json:"timestamp"`
+	Name      string `json:"name"`
+}
+
+// AddReaction adds the emoji reaction name to the message identified by channel and ts.
+// The colons around the name, as in ":white_check_mark:", are optional.
+// Slack responds with "already_reacted" when the message already has the reaction.
+func (c *Client) AddReaction(ctx context.Context, channel, ts, name string) error {
+	return c.react(ctx, "/reactions.add", channel, ts, name)
+}
+
+// RemoveReaction removes the emoji reaction name from the message identified by channel and ts.
+// Slack responds with "no_reaction" when the message does not have the reaction.
+func (c *Client) RemoveReaction(ctx context.Context, channel, ts, name string) error {
+	return c.react(ctx, "/reactions.remove", channel, ts, name)
+}
+
+func (c *Client) react(ctx context.Context, route, channel, ts, name string) error {
+	var response APIResponse
+	req := httpclient.NewRequest("POST", route,
+		httpclient.Body(reactionRequest{Channel: channel, Timestamp: ts, Name: strings.Trim(name, ":")}),
+		httpclient.JSONDecoder(&response),
+	)
+
+	err := c.call(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if response.Error != "" {
+		return &APIError{Code: response.Error}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package slack
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/circleci/ex/testing/testcontext"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func Test_Reactions(t *testing.T) {
+	ctx := testcontext.Background()
+
+	reactions := map[string]bool{}
+	var last reactionRequest
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/reactions.add", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &last)
+		if reactions[last.Name] {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "already_reacted"}`))
+			return
+		}
+		reactions[last.Name] = true
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	})
+	mux.HandleFunc("/reactions.remove", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &last)
+		if !reactions[last.Name] {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "no_reaction"}`))
+			return
+		}
+		delete(reactions, last.Name)
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	})
+
+	client := NewClient(ClientOptions{BaseURL: server.URL, SlackToken: "faketoken"})
+
+	t.Run("add", func(t *testing.T) {
+		err := client.AddReaction(ctx, "C123", "1700000000.000100", ":hourglass:")
+		assert.NilError(t, err)
+		assert.Check(t, cmp.DeepEqual(last, reactionRequest{Channel: "C123", Timestamp: "1700000000.000100", Name: "hourglass"}))
+	})
+
+	t.Run("already_reacted", func(t *testing.T) {
+		err := client.AddReaction(ctx, "C123", "1700000000.000100", "hourglass")
+		assert.Check(t, IsAPIError(err, "already_reacted"))
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		err := client.RemoveReaction(ctx, "C123", "1700000000.000100", "hourglass")
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Len(reactions, 0))
+	})
+
+	t.Run("no_reaction", func(t *testing.T) {
+		err := client.RemoveReaction(ctx, "C123", "1700000000.000100", "hourglass")
+		assert.Check(t, IsAPIError(err, "no_reaction"))
+	})
+}
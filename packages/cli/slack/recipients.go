@@ -0,0 +1,67 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Types of the recipients in a channel list.
+const (
+	// RecipientChannel posts the message in a channel, such as "releases" or "C0123456789".
+	RecipientChannel = "channel"
+	// RecipientUser sends the message as a direct message to a user identified by
+	// its email address or ID, as in "user:alice@example.com" or "user:U0123456789".
+	RecipientUser = "user"
+	// RecipientGroup sends the message as a direct message to every member of a
+	// user group identified by its handle, as in "group:oncall".
+	RecipientGroup = "group"
+)
+
+// Recipient is an entry of a channel list.
+type Recipient struct {
+	// Workspace is the name of the workspace the recipient is in, or empty for the default workspace.
+	Workspace string
+	Type      string
+	// Name is the channel, the email address or ID of the user, or the handle of the user group.
+	Name string
+}
+
+// ParseRecipient returns the recipient described by an entry of a channel list.
+// Entries without a "user:" or "group:" prefix are channels. Any other prefix is the name of
+// a workspace, as in "status:incidents" or "status:user:alice@example.com".
+func ParseRecipient(entry string) (Recipient, error) {
+	prefix, rest, ok := strings.Cut(entry, ":")
+	if !ok {
+		return Recipient{Type: RecipientChannel, Name: entry}, nil
+	}
+	if IsRecipientType(prefix) {
+		name := strings.TrimPrefix(strings.TrimSpace(rest), "@")
+		if name == "" {
+			return Recipient{}, fmt.Errorf("the recipient %q has no %s", entry, prefix)
+		}
+		return Recipient{Type: prefix, Name: name}, nil
+	}
+
+	if prefix == "" {
+		return Recipient{}, fmt.Errorf("the recipient %q has no workspace", entry)
+	}
+	recipient, err := ParseRecipient(rest)
+	if err != nil {
+		return Recipient{}, err
+	}
+	if recipient.Workspace != "" || recipient.Name == "" {
+		return Recipient{}, fmt.Errorf("invalid recipient %q, expected <workspace>:<channel>", entry)
+	}
+	recipient.Workspace = prefix
+	return recipient, nil
+}
+
+// IsRecipientType reports whether prefix is a recipient type rather than the name of a workspace.
+func IsRecipientType(prefix string) bool {
+	return prefix == RecipientUser || prefix == RecipientGroup
+}
+
+// IsEmail reports whether the name of a user recipient is an email address rather than an ID.
+func (r Recipient) IsEmail() bool {
+	return r.Type == RecipientUser && strings.Contains(r.Name, "@")
+}
@@ -0,0 +1,64 @@
+package slack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRecipient(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    string
+		expected Recipient
+		isEmail  bool
+		err      string
+	}{
+		{name: "channel", entry: "releases", expected: Recipient{Type: RecipientChannel, Name: "releases"}},
+		{name: "channel id", entry: "C0123456789", expected: Recipient{Type: RecipientChannel, Name: "C0123456789"}},
+		{
+			name:     "user email",
+			entry:    "user:alice@example.com",
+			expected: Recipient{Type: RecipientUser, Name: "alice@example.com"},
+			isEmail:  true,
+		},
+		{name: "user id", entry: "user:U0123456789", expected: Recipient{Type: RecipientUser, Name: "U0123456789"}},
+		{name: "group", entry: "group:oncall", expected: Recipient{Type: RecipientGroup, Name: "oncall"}},
+		{name: "group with @", entry: "group:@oncall", expected: Recipient{Type: RecipientGroup, Name: "oncall"}},
+		{
+			name:     "workspace channel",
+			entry:    "status:incidents",
+			expected: Recipient{Workspace: "status", Type: RecipientChannel, Name: "incidents"},
+		},
+		{
+			name:     "workspace user",
+			entry:    "status:user:alice@example.com",
+			expected: Recipient{Workspace: "status", Type: RecipientUser, Name: "alice@example.com"},
+			isEmail:  true,
+		},
+		{
+			name:     "workspace group",
+			entry:    "status:group:oncall",
+			expected: Recipient{Workspace: "status", Type: RecipientGroup, Name: "oncall"},
+		},
+		{name: "empty user", entry: "user:", err: `the recipient "user:" has no user`},
+		{name: "empty workspace", entry: ":incidents", err: `the recipient ":incidents" has no workspace`},
+		{name: "empty workspace channel", entry: "status:", err: `invalid recipient "status:", expected <workspace>:<channel>`},
+		{name: "nested workspaces", entry: "eng:status:incidents", err: `invalid recipient "eng:status:incidents", expected <workspace>:<channel>`},
+		{name: "empty workspace user", entry: "status:user:", err: `the recipient "user:" has no user`},
+		{name: "empty group", entry: "group: ", err: `the recipient "group: " has no group`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipient, err := ParseRecipient(tt.entry)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, recipient)
+			assert.Equal(t, tt.isEmail, recipient.IsEmail())
+		})
+	}
+}
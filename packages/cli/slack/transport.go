@@ -0,0 +1,81 @@
+package slack
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// TransportOptions configure how the client connects to Slack, such as from behind
+// an egress proxy intercepting TLS.
+type TransportOptions struct {
+	// CABundle is the path of a PEM file of certificate authorities trusted in addition
+	// to the ones of the system, such as the one of the proxy.
+	CABundle string
+	// ClientCert and ClientKey are the paths of the PEM certificate and key presented
+	// to servers requesting a client certificate. Both must be set to use one.
+	ClientCert string
+	ClientKey  string
+	// Proxy is read from the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables when it is nil.
+	Proxy *httpproxy.Config
+}
+
+// NewTransport returns the transport to pass in ClientOptions.
+func NewTransport(options TransportOptions) (*http.Transport, error) {
+	tlsConfig, err := options.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := options.Proxy
+	if proxy == nil {
+		proxy = httpproxy.FromEnvironment()
+	}
+	proxyFunc := proxy.ProxyFunc()
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = func(r *http.Request) (*url.URL, error) {
+		return proxyFunc(r.URL)
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+func (o TransportOptions) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if o.CABundle != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		//nolint:gosec // G304 the path is provided by the user
+		bundle, err := os.ReadFile(o.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the CA bundle: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("the CA bundle %s contains no PEM certificate", o.CABundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if (o.ClientCert == "") != (o.ClientKey == "") {
+		return nil, errors.New("both the client certificate and the client key must be provided")
+	}
+	if o.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(o.ClientCert, o.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,194 @@
+package slack
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/circleci/ex/testing/testcontext"
+	"golang.org/x/net/http/httpproxy"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func TestNewTransport_TLS(t *testing.T) {
+	ctx := testcontext.Background()
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	caBundle := writePEM(t, dir, "ca.pem", "CERTIFICATE", ca.cert.Raw)
+	serverCert := ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	clientCert := ca.issue(t, "client", x509.ExtKeyUsageClientAuth)
+	clientCertFile := writePEM(t, dir, "client.pem", "CERTIFICATE", clientCert.Certificate[0])
+	clientKeyDER, err := x509.MarshalECPrivateKey(clientCert.PrivateKey.(*ecdsa.PrivateKey))
+	assert.NilError(t, err)
+	clientKeyFile := writePEM(t, dir, "client-key.pem", "EC PRIVATE KEY", clientKeyDER)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "permalink": "https://example.slack.com/archives/C123/p1"}`))
+	})
+	server := httptest.NewUnstartedServer(handler)
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{serverCert}, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	mtlsServer := httptest.NewUnstartedServer(handler)
+	mtlsServer.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    ca.pool(),
+		MinVersion:   tls.VersionTLS12,
+	}
+	mtlsServer.StartTLS()
+	t.Cleanup(mtlsServer.Close)
+
+	get := func(t *testing.T, options TransportOptions, url string) error {
+		t.Helper()
+		transport, err := NewTransport(options)
+		assert.NilError(t, err)
+		resp, err := (&http.Client{Transport: transport}).Get(url)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	t.Run("untrusted CA", func(t *testing.T) {
+		err := get(t, TransportOptions{}, server.URL)
+		assert.Check(t, cmp.ErrorContains(err, "certificate signed by unknown authority"))
+	})
+
+	t.Run("CA bundle", func(t *testing.T) {
+		transport, err := NewTransport(TransportOptions{CABundle: caBundle})
+		assert.NilError(t, err)
+		client := NewClient(ClientOptions{BaseURL: server.URL, SlackToken: "faketoken", Transport: transport})
+
+		permalink, err := client.GetPermalink(ctx, "C123", "1")
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(permalink, "https://example.slack.com/archives/C123/p1"))
+	})
+
+	t.Run("missing client certificate", func(t *testing.T) {
+		err := get(t, TransportOptions{CABundle: caBundle}, mtlsServer.URL)
+		assert.Check(t, err != nil)
+	})
+
+	t.Run("client certificate", func(t *testing.T) {
+		err := get(t, TransportOptions{CABundle: caBundle, ClientCert: clientCertFile, ClientKey: clientKeyFile}, mtlsServer.URL)
+		assert.NilError(t, err)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := NewTransport(TransportOptions{CABundle: filepath.Join(dir, "missing.pem")})
+		assert.Check(t, cmp.ErrorContains(err, "unable to read the CA bundle"))
+
+		_, err = NewTransport(TransportOptions{CABundle: clientKeyFile})
+		assert.Check(t, cmp.ErrorContains(err, "contains no PEM certificate"))
+
+		_, err = NewTransport(TransportOptions{ClientCert: clientCertFile})
+		assert.Check(t, cmp.Error(err, "both the client certificate and the client key must be provided"))
+
+		_, err = NewTransport(TransportOptions{ClientCert: clientCertFile, ClientKey: caBundle})
+		assert.Check(t, cmp.ErrorContains(err, "unable to load the client certificate"))
+	})
+}
+
+func TestNewTransport_Proxy(t *testing.T) {
+	proxyURL := func(t *testing.T, transport *http.Transport, target string) string {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, target, nil)
+		assert.NilError(t, err)
+		proxy, err := transport.Proxy(req)
+		assert.NilError(t, err)
+		if proxy == nil {
+			return ""
+		}
+		return proxy.String()
+	}
+
+	t.Run("config", func(t *testing.T) {
+		transport, err := NewTransport(TransportOptions{Proxy: &httpproxy.Config{
+			HTTPSProxy: "http://proxy.example.com:3128",
+			NoProxy:    "internal.example.com",
+		}})
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(proxyURL(t, transport, "https://slack.com/api/chat.postMessage"), "http://proxy.example.com:3128"))
+		assert.Check(t, cmp.Equal(proxyURL(t, transport, "https://internal.example.com/api"), ""))
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv("HTTPS_PROXY", "http://env-proxy.example.com:8080")
+		t.Setenv("NO_PROXY", ".files.slack.com")
+		transport, err := NewTransport(TransportOptions{})
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(proxyURL(t, transport, "https://slack.com/api/chat.postMessage"), "http://env-proxy.example.com:8080"))
+		assert.Check(t, cmp.Equal(proxyURL(t, transport, "https://uploads.files.slack.com/upload"), ""))
+	})
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NilError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NilError(t, err)
+	return testCA{cert: cert, key: key}
+}
+
+func (ca testCA) issue(t *testing.T, name string, usage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	assert.NilError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func (ca testCA) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	assert.NilError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
@@ -0,0 +1,95 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/circleci/ex/httpclient"
+)
+
+type lookupUserResponse struct {
+	APIResponse
+	User struct {
+		ID string `json:"id"`
+	} `json:"user"`
+}
+
+type userGroupsResponse struct {
+	APIResponse
+	UserGroups []struct {
+		ID     string `json:"id"`
+		Handle string `json:"handle"`
+	} `json:"usergroups"`
+}
+
+type userGroupUsersResponse struct {
+	APIResponse
+	Users []string `json:"users"`
+}
+
+// LookupUserByEmail returns the ID of the user with the email address.
+// Slack responds with "users_not_found" when there is no such user.
+func (c *Client) LookupUserByEmail(ctx context.Context, email string) (string, error) {
+	var response lookupUserResponse
+	req := httpclient.NewRequest("GET", "/users.lookupByEmail",
+		httpclient.QueryParam("email", email),
+		httpclient.JSONDecoder(&response),
+	)
+
+	err := c.call(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	if response.Error != "" {
+		return "", &APIError{Code: response.Error}
+	}
+	return response.User.ID, nil
+}
+
+// UserGroupMembers returns the IDs of the users in the user group identified by its handle,
+// as in "@oncall" without the "@", or by its ID.
+func (c *Client) UserGroupMembers(ctx context.Context, group string) ([]string, error) {
+	id, err := c.userGroupID(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+
+	var response userGroupUsersResponse
+	req := httpclient.NewRequest("GET", "/usergroups.users.list",
+		httpclient.QueryParam("usergroup", id),
+		httpclient.JSONDecoder(&response),
+	)
+
+	err = c.call(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != "" {
+		return nil, &APIError{Code: response.Error}
+	}
+	return response.Users, nil
+}
+
+func (c *Client) userGroupID(ctx context.Context, group string) (string, error) {
+	var response userGroupsResponse
+	req := httpclient.NewRequest("GET", "/usergroups.list",
+		httpclient.JSONDecoder(&response),
+	)
+
+	err := c.call(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	if response.Error != "" {
+		return "", &APIError{Code: response.Error}
+	}
+	for _, userGroup := range response.UserGroups {
+		if userGroup.Handle == group || userGroup.ID == group {
+			return userGroup.ID, nil
+		}
+	}
+	return "", fmt.Errorf("there is no user group with the handle %q", group)
+}
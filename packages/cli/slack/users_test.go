@@ -0,0 +1,79 @@
+package slack
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/circleci/ex/testing/testcontext"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func Test_Lookup_User_By_Email(t *testing.T) {
+	ctx := testcontext.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("email") != "alice@example.com" {
+			_, _ = w.Write([]byte(`{"ok": false, "error": "users_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "user": {"id": "U123", "name": "alice"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(ClientOptions{BaseURL: server.URL, SlackToken: "faketoken"})
+
+	t.Run("successful", func(t *testing.T) {
+		user, err := client.LookupUserByEmail(ctx, "alice@example.com")
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Equal(user, "U123"))
+	})
+
+	t.Run("users_not_found", func(t *testing.T) {
+		_, err := client.LookupUserByEmail(ctx, "bob@example.com")
+		assert.Check(t, IsAPIError(err, "users_not_found"))
+	})
+}
+
+func Test_User_Group_Members(t *testing.T) {
+	ctx := testcontext.Background()
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/usergroups.list", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok": true, "usergroups": [{"id": "S123", "handle": "oncall"}, {"id": "S456", "handle": "empty"}]}`))
+	})
+	mux.HandleFunc("/usergroups.users.list", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("usergroup") != "S123" {
+			_, _ = w.Write([]byte(`{"ok": true, "users": []}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true, "users": ["U123", "U456"]}`))
+	})
+
+	client := NewClient(ClientOptions{BaseURL: server.URL, SlackToken: "faketoken"})
+
+	t.Run("handle", func(t *testing.T) {
+		users, err := client.UserGroupMembers(ctx, "oncall")
+		assert.NilError(t, err)
+		assert.Check(t, cmp.DeepEqual(users, []string{"U123", "U456"}))
+	})
+
+	t.Run("id", func(t *testing.T) {
+		users, err := client.UserGroupMembers(ctx, "S123")
+		assert.NilError(t, err)
+		assert.Check(t, cmp.DeepEqual(users, []string{"U123", "U456"}))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		users, err := client.UserGroupMembers(ctx, "empty")
+		assert.NilError(t, err)
+		assert.Check(t, cmp.Len(users, 0))
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := client.UserGroupMembers(ctx, "nobody")
+		assert.Check(t, cmp.Error(err, `there is no user group with the handle "nobody"`))
+	})
+}
@@ -0,0 +1,146 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Policies applied to notifications sent outside the delivery window.
+const (
+	// DeliveryWindowPolicySkip skips every notification outside the window.
+	DeliveryWindowPolicySkip = "skip"
+	// DeliveryWindowPolicyDefer schedules notifications for the start of the next window.
+	DeliveryWindowPolicyDefer = "defer"
+	// DeliveryWindowPolicyFailuresOnly lets failures through and skips everything else.
+	DeliveryWindowPolicyFailuresOnly = "failures-only"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// DeliveryWindow is a recurring period of the week during which notifications are delivered,
+// such as "Mon-Fri 08:00-19:00". A window ending before it starts spans midnight.
+type DeliveryWindow struct {
+	spec     string
+	days     [7]bool
+	start    time.Duration
+	end      time.Duration
+	location *time.Location
+}
+
+// ParseDeliveryWindow parses a window in the "<days> <start>-<end>" format, where days is
+// a comma separated list of days or day ranges such as "Mon-Fri" or "Mon,Wed,Fri".
+// The days can be omitted to use every day. Times are interpreted in loc.
+func ParseDeliveryWindow(spec string, loc *time.Location) (*DeliveryWindow, error) {
+	w := &DeliveryWindow{spec: spec, location: loc}
+
+	fields := strings.Fields(spec)
+	var days, hours string
+	switch len(fields) {
+	case 1:
+		days, hours = "sun-sat", fields[0]
+	case 2:
+		days, hours = fields[0], fields[1]
+	default:
+		return nil, fmt.Errorf("invalid delivery window %q: must be in the format \"Mon-Fri 08:00-19:00\"", spec)
+	}
+
+	if err := w.parseDays(days); err != nil {
+		return nil, fmt.Errorf("invalid delivery window %q: %w", spec, err)
+	}
+	if err := w.parseHours(hours); err != nil {
+		return nil, fmt.Errorf("invalid delivery window %q: %w", spec, err)
+	}
+	return w, nil
+}
+
+func (w *DeliveryWindow) parseDays(days string) error {
+	for _, part := range strings.Split(strings.ToLower(days), ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		first, ok := weekdays[from]
+		if !ok {
+			return fmt.Errorf("unknown day %q", from)
+		}
+		last := first
+		if isRange {
+			if last, ok = weekdays[to]; !ok {
+				return fmt.Errorf("unknown day %q", to)
+			}
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			w.days[d] = true
+			if d == last {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (w *DeliveryWindow) parseHours(hours string) error {
+	from, to, ok := strings.Cut(hours, "-")
+	if !ok {
+		return fmt.Errorf("hours %q must be in the format \"08:00-19:00\"", hours)
+	}
+	start, err := time.Parse("15:04", from)
+	if err != nil {
+		return fmt.Errorf("invalid start time %q", from)
+	}
+	end, err := time.Parse("15:04", to)
+	if err != nil {
+		return fmt.Errorf("invalid end time %q", to)
+	}
+	w.start = time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute
+	w.end = time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute
+	if w.start == w.end {
+		return fmt.Errorf("hours %q must not start and end at the same time", hours)
+	}
+	return nil
+}
+
+// String returns the window as it was configured.
+func (w *DeliveryWindow) String() string {
+	return w.spec
+}
+
+// Contains reports whether t falls inside the window.
+func (w *DeliveryWindow) Contains(t time.Time) bool {
+	t = t.In(w.location)
+	// Use the wall clock rather than the elapsed time, which is off by an hour on DST changes
+	sinceMidnight := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+
+	if w.start < w.end {
+		return w.days[t.Weekday()] && sinceMidnight >= w.start && sinceMidnight < w.end
+	}
+	// The window spans midnight, so its early hours belong to the window that started the day before.
+	if sinceMidnight >= w.start {
+		return w.days[t.Weekday()]
+	}
+	return sinceMidnight < w.end && w.days[(t.Weekday()+6)%7]
+}
+
+// Next returns the start of the next window after t, or t itself if it falls inside the window.
+func (w *DeliveryWindow) Next(t time.Time) time.Time {
+	if w.Contains(t) {
+		return t
+	}
+	local := t.In(w.location)
+	for i := 0; i <= 7; i++ {
+		start := time.Date(local.Year(), local.Month(), local.Day()+i,
+			int(w.start/time.Hour), int(w.start%time.Hour/time.Minute), 0, 0, w.location)
+		if w.days[start.Weekday()] && start.After(t) {
+			return start
+		}
+	}
+	// Unreachable, since parsing guarantees at least one day is in the window.
+	return t
+}
@@ -0,0 +1,141 @@
+package slack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDeliveryWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr bool
+	}{
+		{name: "day range and hours", spec: "Mon-Fri 08:00-19:00"},
+		{name: "day list", spec: "mon,wed,fri 09:00-17:00"},
+		{name: "hours only", spec: "08:00-19:00"},
+		{name: "overnight", spec: "Fri-Sat 22:00-02:00"},
+		{name: "unknown day", spec: "Mon-Funday 08:00-19:00", wantErr: true},
+		{name: "missing end time", spec: "Mon-Fri 08:00", wantErr: true},
+		{name: "invalid time", spec: "Mon-Fri 08:00-25:00", wantErr: true},
+		{name: "empty window", spec: "Mon-Fri 08:00-08:00", wantErr: true},
+		{name: "too many fields", spec: "Mon-Fri 08:00-19:00 UTC", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := ParseDeliveryWindow(tt.spec, time.UTC)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.spec, w.String())
+		})
+	}
+}
+
+func TestDeliveryWindowContains(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		spec string
+		time time.Time
+		want bool
+	}{
+		{
+			name: "weekday inside the window",
+			spec: "Mon-Fri 08:00-19:00",
+			time: time.Date(2024, time.January, 15, 10, 0, 0, 0, berlin), // Monday
+			want: true,
+		},
+		{
+			name: "weekday before the window",
+			spec: "Mon-Fri 08:00-19:00",
+			time: time.Date(2024, time.January, 15, 3, 0, 0, 0, berlin),
+			want: false,
+		},
+		{
+			name: "end of the window is excluded",
+			spec: "Mon-Fri 08:00-19:00",
+			time: time.Date(2024, time.January, 15, 19, 0, 0, 0, berlin),
+			want: false,
+		},
+		{
+			name: "weekend",
+			spec: "Mon-Fri 08:00-19:00",
+			time: time.Date(2024, time.January, 13, 10, 0, 0, 0, berlin), // Saturday
+			want: false,
+		},
+		{
+			name: "evaluated in the window time zone",
+			spec: "Mon-Fri 08:00-19:00",
+			time: time.Date(2024, time.January, 15, 7, 30, 0, 0, time.UTC), // 08:30 in Berlin
+			want: true,
+		},
+		{
+			name: "overnight window after midnight",
+			spec: "Fri 22:00-02:00",
+			time: time.Date(2024, time.January, 13, 1, 0, 0, 0, berlin), // Saturday
+			want: true,
+		},
+		{
+			name: "overnight window after midnight on the wrong day",
+			spec: "Fri 22:00-02:00",
+			time: time.Date(2024, time.January, 12, 1, 0, 0, 0, berlin), // Friday
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := ParseDeliveryWindow(tt.spec, berlin)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, w.Contains(tt.time))
+		})
+	}
+}
+
+func TestDeliveryWindowNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+	w, err := ParseDeliveryWindow("Mon-Fri 08:00-19:00", berlin)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		time time.Time
+		want time.Time
+	}{
+		{
+			name: "inside the window",
+			time: time.Date(2024, time.January, 15, 10, 0, 0, 0, berlin),
+			want: time.Date(2024, time.January, 15, 10, 0, 0, 0, berlin),
+		},
+		{
+			name: "early in the morning",
+			time: time.Date(2024, time.January, 15, 3, 0, 0, 0, berlin),
+			want: time.Date(2024, time.January, 15, 8, 0, 0, 0, berlin),
+		},
+		{
+			name: "in the evening",
+			time: time.Date(2024, time.January, 15, 21, 0, 0, 0, berlin),
+			want: time.Date(2024, time.January, 16, 8, 0, 0, 0, berlin),
+		},
+		{
+			name: "friday evening",
+			time: time.Date(2024, time.January, 19, 21, 0, 0, 0, berlin),
+			want: time.Date(2024, time.January, 22, 8, 0, 0, 0, berlin),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.want.Equal(w.Next(tt.time)), "expected %v, got %v", tt.want, w.Next(tt.time))
+		})
+	}
+}
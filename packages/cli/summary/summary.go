@@ -0,0 +1,165 @@
+// Package summary records the outcome of the jobs of a workflow and summarizes them in a single message.
+//
+// Each job writes a Record to a directory shared with the last job of the workflow, such as a workspace,
+// which reads them back and exposes the summary as built-in variables.
+package summary
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record is the outcome of a job.
+type Record struct {
+	Job string `json:"job"`
+	// Node is the index of the parallel node that ran the job, when it is known.
+	Node   string `json:"node,omitempty"`
+	Status string `json:"status"`
+	// Duration is the time the job took, such as "4m12s", when it is known.
+	Duration   string    `json:"duration,omitempty"`
+	URL        string    `json:"url,omitempty"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// unsafeFileChars matches the characters replaced in the file name of a record.
+var unsafeFileChars = regexp.MustCompile(`[^\w.-]+`)
+
+// WriteRecord writes the record to dir, replacing the previous record of the same job and node,
+// and returns the path of the record file.
+func WriteRecord(dir string, record Record) (string, error) {
+	if record.Job == "" {
+		return "", errors.New("the job name is unknown")
+	}
+	content, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("unable to create the record directory: %w", err)
+	}
+	name := unsafeFileChars.ReplaceAllString(record.Job, "_")
+	if record.Node != "" {
+		name += "." + unsafeFileChars.ReplaceAllString(record.Node, "_")
+	}
+	path := filepath.Join(dir, name+".json")
+	//nolint:gosec // the records are meant to be shared with other jobs
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return "", fmt.Errorf("unable to write the job record: %w", err)
+	}
+	return path, nil
+}
+
+// ReadRecords reads the records in dir, in the order they were recorded.
+func ReadRecords(dir string) ([]Record, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(paths))
+	for _, path := range paths {
+		//nolint:gosec // G304 the directory is provided by the user on purpose
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the job record: %w", err)
+		}
+		var record Record
+		if err := json.Unmarshal(content, &record); err != nil {
+			return nil, fmt.Errorf("unable to parse the job record %q: %w", path, err)
+		}
+		records = append(records, record)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].RecordedAt.Before(records[j].RecordedAt)
+	})
+	return records, nil
+}
+
+// Summary is the outcome of the jobs of a workflow.
+type Summary struct {
+	Records []Record
+}
+
+// Jobs returns one record per job, in the order the jobs were first recorded. A job recorded by
+// several nodes failed when any of them failed, and its record is the one of the first failure.
+func (s Summary) Jobs() []Record {
+	var jobs []Record
+	index := map[string]int{}
+	for _, record := range s.Records {
+		i, ok := index[record.Job]
+		if !ok {
+			index[record.Job] = len(jobs)
+			jobs = append(jobs, record)
+			continue
+		}
+		if record.Status != "pass" && jobs[i].Status == "pass" {
+			jobs[i] = record
+		}
+	}
+	return jobs
+}
+
+// Failed returns the number of jobs that failed on any of their nodes.
+func (s Summary) Failed() int {
+	failed := 0
+	for _, job := range s.Jobs() {
+		if job.Status != "pass" {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Status returns "fail" if any job failed, and "pass" otherwise.
+func (s Summary) Status() string {
+	if s.Failed() > 0 {
+		return "fail"
+	}
+	return "pass"
+}
+
+var mrkdwnEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// EnvVars returns the summary as built-in variables:
+//
+//   - SLACK_ORB_WORKFLOW_STATUS: "fail" if any job failed, and "pass" otherwise.
+//   - SLACK_ORB_WORKFLOW_JOBS: a mrkdwn list of the jobs with their status emoji, duration when it is known, and link.
+//   - SLACK_ORB_WORKFLOW_JOBS_TOTAL, SLACK_ORB_WORKFLOW_JOBS_PASSED and SLACK_ORB_WORKFLOW_JOBS_FAILED.
+func (s Summary) EnvVars() map[string]string {
+	jobs := s.Jobs()
+	var lines []string
+	for _, record := range jobs {
+		emoji := ":white_check_mark:"
+		if record.Status != "pass" {
+			emoji = ":x:"
+		}
+		job := mrkdwnEscaper.Replace(record.Job)
+		if record.URL != "" {
+			job = fmt.Sprintf("<%s|%s>", record.URL, job)
+		}
+		line := emoji + " " + job
+		if record.Duration != "" {
+			line += " (" + record.Duration + ")"
+		}
+		lines = append(lines, line)
+	}
+
+	failed := s.Failed()
+	return map[string]string{
+		"SLACK_ORB_WORKFLOW_STATUS":      s.Status(),
+		"SLACK_ORB_WORKFLOW_JOBS":        strings.Join(lines, "\n"),
+		"SLACK_ORB_WORKFLOW_JOBS_TOTAL":  strconv.Itoa(len(jobs)),
+		"SLACK_ORB_WORKFLOW_JOBS_PASSED": strconv.Itoa(len(jobs) - failed),
+		"SLACK_ORB_WORKFLOW_JOBS_FAILED": strconv.Itoa(failed),
+	}
+}
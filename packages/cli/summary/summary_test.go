@@ -0,0 +1,96 @@
+package summary
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func TestRecords(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "jobs")
+	recordedAt := time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC)
+
+	records := []Record{
+		{Job: "test/unit", Status: "fail", Duration: "4m12s", URL: "https://circleci.com/gh/org/repo/2", RecordedAt: recordedAt.Add(time.Minute)},
+		{Job: "build", Status: "pass", Duration: "1m0s", URL: "https://circleci.com/gh/org/repo/1", RecordedAt: recordedAt},
+	}
+	for _, record := range records {
+		_, err := WriteRecord(dir, record)
+		assert.NilError(t, err)
+	}
+	path, err := WriteRecord(dir, Record{Job: "lint", Status: "pass", RecordedAt: recordedAt.Add(2 * time.Minute)})
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Equal(path, filepath.Join(dir, "lint.json")))
+
+	read, err := ReadRecords(dir)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.DeepEqual(read, []Record{
+		records[1],
+		records[0],
+		{Job: "lint", Status: "pass", RecordedAt: recordedAt.Add(2 * time.Minute)},
+	}))
+}
+
+func TestRecordsOfParallelNodes(t *testing.T) {
+	dir := t.TempDir()
+	recordedAt := time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC)
+	for i, status := range []string{"pass", "fail", "pass"} {
+		_, err := WriteRecord(dir, Record{
+			Job:        "test",
+			Node:       strconv.Itoa(i),
+			Status:     status,
+			URL:        "https://circleci.com/gh/org/repo/3",
+			RecordedAt: recordedAt.Add(time.Duration(i) * time.Minute),
+		})
+		assert.NilError(t, err)
+	}
+	_, err := WriteRecord(dir, Record{Job: "build", Status: "pass", RecordedAt: recordedAt.Add(-time.Minute)})
+	assert.NilError(t, err)
+
+	records, err := ReadRecords(dir)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Len(records, 4))
+
+	s := Summary{Records: records}
+	assert.Check(t, cmp.Equal(s.Failed(), 1))
+	assert.Check(t, cmp.Equal(s.Status(), "fail"))
+	jobs := s.Jobs()
+	assert.Assert(t, cmp.Len(jobs, 2))
+	assert.Check(t, cmp.Equal(jobs[1].Node, "1"))
+	assert.Check(t, cmp.Equal(s.EnvVars()["SLACK_ORB_WORKFLOW_JOBS_TOTAL"], "2"))
+}
+
+func TestWriteRecordWithoutJob(t *testing.T) {
+	_, err := WriteRecord(t.TempDir(), Record{Status: "pass"})
+	assert.Check(t, cmp.ErrorContains(err, "the job name is unknown"))
+}
+
+func TestReadRecordsInvalid(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "build.json"), []byte("not json"), 0o600))
+
+	_, err := ReadRecords(dir)
+	assert.Check(t, cmp.ErrorContains(err, "unable to parse the job record"))
+}
+
+func TestEnvVars(t *testing.T) {
+	s := Summary{Records: []Record{
+		{Job: "build", Status: "pass", Duration: "1m0s", URL: "https://circleci.com/gh/org/repo/1"},
+		{Job: "test <e2e>", Status: "fail"},
+	}}
+
+	assert.Check(t, cmp.DeepEqual(s.EnvVars(), map[string]string{
+		"SLACK_ORB_WORKFLOW_STATUS": "fail",
+		"SLACK_ORB_WORKFLOW_JOBS": ":white_check_mark: <https://circleci.com/gh/org/repo/1|build> (1m0s)\n" +
+			":x: test &lt;e2e&gt;",
+		"SLACK_ORB_WORKFLOW_JOBS_TOTAL":  "2",
+		"SLACK_ORB_WORKFLOW_JOBS_PASSED": "1",
+		"SLACK_ORB_WORKFLOW_JOBS_FAILED": "1",
+	}))
+	assert.Check(t, cmp.Equal(Summary{Records: s.Records[:1]}.Status(), "pass"))
+}
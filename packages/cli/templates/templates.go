@@ -18,6 +18,15 @@ var (
 
 	//go:embed success_tagged_deploy_1.json
 	successTaggedDeploy string
+
+	//go:embed success_coverage_1.json
+	successCoverage string
+
+	//go:embed test_failure_summary_1.json
+	testFailureSummary string
+
+	//go:embed workflow_summary_1.json
+	workflowSummary string
 )
 
 var (
@@ -30,6 +39,9 @@ var (
 		"basic_fail_1":            basicFail,
 		"basic_success_1":         basicSuccess,
 		"success_tagged_deploy_1": successTaggedDeploy,
+		"success_coverage_1":      successCoverage,
+		"test_failure_summary_1":  testFailureSummary,
+		"workflow_summary_1":      workflowSummary,
 	}
 )
 
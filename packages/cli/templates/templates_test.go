@@ -99,6 +99,36 @@ func TestDetermineMessageBody(t *testing.T) {
 			expected:       ForName("basic_success_1"),
 			hasError:       false,
 		},
+		{
+			name:           "test failure summary template name",
+			templateVar:    "",
+			templatePath:   "",
+			templateInline: "",
+			template:       "test_failure_summary_1",
+			jobStatus:      "fail",
+			expected:       ForName("test_failure_summary_1"),
+			hasError:       false,
+		},
+		{
+			name:           "workflow summary template name",
+			templateVar:    "",
+			templatePath:   "",
+			templateInline: "",
+			template:       "workflow_summary_1",
+			jobStatus:      "fail",
+			expected:       ForName("workflow_summary_1"),
+			hasError:       false,
+		},
+		{
+			name:           "coverage template name",
+			templateVar:    "",
+			templatePath:   "",
+			templateInline: "",
+			template:       "success_coverage_1",
+			jobStatus:      "pass",
+			expected:       ForName("success_coverage_1"),
+			hasError:       false,
+		},
 		{
 			name:           "invalid template name",
 			templateVar:    "",
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestCompileGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		result  bool
+	}{
+		{pattern: "services/api/**", path: "services/api/main.go", result: true},
+		{pattern: "services/api/**", path: "services/api/handlers/users.go", result: true},
+		{pattern: "services/api/**", path: "services/web/index.html", result: false},
+		{pattern: "services/*/Dockerfile", path: "services/api/Dockerfile", result: true},
+		{pattern: "services/*/Dockerfile", path: "services/api/build/Dockerfile", result: false},
+		{pattern: "**/*.go", path: "main.go", result: true},
+		{pattern: "**/*.go", path: "cmd/root.go", result: true},
+		{pattern: "**/*.go", path: "README.md", result: false},
+		{pattern: "docs/?.md", path: "docs/a.md", result: true},
+		{pattern: "go.mod", path: "go.mod", result: true},
+		{pattern: "go.mod", path: "packages/cli/go.mod", result: false},
+		{pattern: "a+b.txt", path: "a+b.txt", result: true},
+	}
+
+	for _, test := range tests {
+		re, err := CompileGlob(test.pattern)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %v", test.pattern, err)
+		}
+		if result := re.MatchString(test.path); result != test.result {
+			t.Errorf("For pattern %q and path %q - expected %v, got %v", test.pattern, test.path, test.result, result)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package utils
 import (
 	"fmt"
 	"regexp"
+	"strconv"
+	"strings"
 )
 
 func IsPatternMatchingString(patternStr string, matchString string) (bool, error) {
@@ -20,3 +22,32 @@ func IsEventMatchingStatus(eventToSendMessage string, jobStatus string) bool {
 func IsPostConditionMet(branchMatches bool, tagMatches bool, invertMatch bool) bool {
 	return (branchMatches || tagMatches) != invertMatch
 }
+
+var byteSizeUnits = map[string]int64{
+	"":   1,
+	"B":  1,
+	"KB": 1 << 10,
+	"MB": 1 << 20,
+	"GB": 1 << 30,
+}
+
+// ParseByteSize parses a size such as "512KB", "10MB" or "1024".
+// Units are binary, so "1KB" is 1024 bytes.
+func ParseByteSize(value string) (int64, error) {
+	trimmed := strings.ToUpper(strings.TrimSpace(value))
+	unitStart := strings.IndexFunc(trimmed, func(r rune) bool { return r < '0' || r > '9' })
+	if unitStart == -1 {
+		unitStart = len(trimmed)
+	}
+	number, unit := trimmed[:unitStart], strings.TrimSpace(trimmed[unitStart:])
+
+	multiplier, ok := byteSizeUnits[unit]
+	if !ok || number == "" {
+		return 0, fmt.Errorf("invalid size %q: must be a number optionally followed by B, KB, MB or GB", value)
+	}
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", value, err)
+	}
+	return n * multiplier, nil
+}
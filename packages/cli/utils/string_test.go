@@ -86,3 +86,37 @@ func TestIsPatternMatchingString(t *testing.T) {
 		}
 	}
 }
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		value   string
+		result  int64
+		wantErr bool
+	}{
+		{value: "1024", result: 1024},
+		{value: "100B", result: 100},
+		{value: "512KB", result: 512 << 10},
+		{value: "10MB", result: 10 << 20},
+		{value: "10 mb", result: 10 << 20},
+		{value: "1GB", result: 1 << 30},
+		{value: "", wantErr: true},
+		{value: "MB", wantErr: true},
+		{value: "10TB", wantErr: true},
+		{value: "1.5MB", wantErr: true},
+	}
+
+	for _, test := range tests {
+		result, err := ParseByteSize(test.value)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("Expected an error for %q, got %d", test.value, result)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", test.value, err)
+		} else if result != test.result {
+			t.Errorf("For %q - expected %d, got %d", test.value, test.result, result)
+		}
+	}
+}
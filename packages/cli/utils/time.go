@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var postAtLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// ParsePostAt parses the time at which a message should be posted.
+// The value is either a duration relative to now, such as "90m" or "+2h", or an absolute time.
+// Absolute times are accepted in RFC3339 format, as "2006-01-02 15:04" which is interpreted in loc,
+// or as a clock time such as "09:00" which refers to its next occurrence in loc.
+// An empty value returns the zero time.
+func ParsePostAt(value string, now time.Time, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if d, err := time.ParseDuration(strings.TrimPrefix(value, "+")); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("invalid post time %q: the duration must be positive", value)
+		}
+		return now.Add(d), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	for _, layout := range postAtLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	if clock, err := time.Parse("15:04", value); err == nil {
+		localNow := now.In(loc)
+		t := time.Date(localNow.Year(), localNow.Month(), localNow.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf(
+		"invalid post time %q: must be a duration such as \"2h\" or a time such as \"2006-01-02 15:04\" or \"09:00\"", value)
+}
+
+// LoadLocation returns the location with the given IANA name, such as "Europe/Berlin".
+// An empty name returns the local time zone.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", name, err)
+	}
+	return loc, nil
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePostAt(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("Couldn't load time zone: %v", err)
+	}
+	// 03:00 in Berlin
+	now := time.Date(2024, time.January, 15, 2, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		description string
+		value       string
+		expected    time.Time
+		expectedErr bool
+	}{
+		{
+			description: "Empty",
+			value:       "",
+			expected:    time.Time{},
+		},
+		{
+			description: "Duration",
+			value:       "90m",
+			expected:    now.Add(90 * time.Minute),
+		},
+		{
+			description: "DurationWithPlusSign",
+			value:       "+6h",
+			expected:    now.Add(6 * time.Hour),
+		},
+		{
+			description: "NegativeDuration",
+			value:       "-1h",
+			expectedErr: true,
+		},
+		{
+			description: "RFC3339",
+			value:       "2024-01-15T09:00:00Z",
+			expected:    time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			description: "DateTimeInLocation",
+			value:       "2024-01-15 09:00",
+			expected:    time.Date(2024, time.January, 15, 9, 0, 0, 0, berlin),
+		},
+		{
+			description: "ClockTimeLaterToday",
+			value:       "09:00",
+			expected:    time.Date(2024, time.January, 15, 9, 0, 0, 0, berlin),
+		},
+		{
+			description: "ClockTimeTomorrow",
+			value:       "02:30",
+			expected:    time.Date(2024, time.January, 16, 2, 30, 0, 0, berlin),
+		},
+		{
+			description: "Invalid",
+			value:       "tomorrow morning",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got, err := ParsePostAt(test.value, now, berlin)
+			if (err != nil) != test.expectedErr {
+				t.Fatalf("Expected error: %v, got: %v", test.expectedErr, err)
+			}
+			if !got.Equal(test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestLoadLocation(t *testing.T) {
+	loc, err := LoadLocation("")
+	if err != nil || loc != time.Local {
+		t.Errorf("Expected the local time zone, got: %v, %v", loc, err)
+	}
+
+	loc, err = LoadLocation("America/New_York")
+	if err != nil || loc.String() != "America/New_York" {
+		t.Errorf("Expected America/New_York, got: %v, %v", loc, err)
+	}
+
+	if _, err := LoadLocation("Mars/Olympus_Mons"); err == nil {
+		t.Errorf("Expected an error for an unknown time zone")
+	}
+}